@@ -17,6 +17,7 @@ Sniperkit-Bot
 // * A terminal node (leaf node) can contain a token and a value.
 // * A nil node (leaf node) only contains a position and evaluates to nil
 // * A non-terminal node (branch node) can contain a token and multiple child nodes.
+// * An error node (leaf node) contains a syntax error the parser recovered from and evaluates to the error
 //
 // Interpreters
 //
@@ -70,3 +70,38 @@ func SetReaderPos(node parsley.Node, f func(parsley.Pos) parsley.Pos) parsley.No
 	}
 	return node
 }
+
+// Errors returns with all the syntax errors stored in error nodes in the order they appear in the tree
+// If the node is a node list then only the first node is checked.
+func Errors(node parsley.Node) []parsley.Error {
+	var errs []parsley.Error
+	collectErrors(node, &errs)
+	return errs
+}
+
+func collectErrors(node parsley.Node, errs *[]parsley.Error) {
+	switch n := node.(type) {
+	case *ErrorNode:
+		*errs = append(*errs, n.Err())
+	case *NonTerminalNode:
+		for _, child := range n.Children() {
+			collectErrors(child, errs)
+		}
+	case NodeList:
+		if len(n) > 0 {
+			collectErrors(n[0], errs)
+		}
+	}
+}
+
+// NodeSpan returns with the span of the node from its position to its reader position
+// If the node is a node list then the span of the first node is returned.
+func NodeSpan(node parsley.Node) parsley.Span {
+	if nl, ok := node.(NodeList); ok {
+		if len(nl) == 0 {
+			return parsley.NewSpan(parsley.NilPos, parsley.NilPos)
+		}
+		node = nl[0]
+	}
+	return parsley.NewSpan(node.Pos(), node.ReaderPos())
+}
@@ -148,3 +148,57 @@ var _ = Describe("SetReaderPos", func() {
 		Expect(func() { ast.SetReaderPos(node, f) }).To(Panic())
 	})
 })
+
+var _ = Describe("Errors", func() {
+	var (
+		err1, err2 parsley.Error
+		e1, e2     *ast.ErrorNode
+		n1         *ast.TerminalNode
+	)
+
+	BeforeEach(func() {
+		err1 = parsley.NewErrorf(parsley.Pos(1), "err1")
+		err2 = parsley.NewErrorf(parsley.Pos(3), "err2")
+		e1 = ast.NewErrorNode(err1, parsley.Pos(1), parsley.Pos(2))
+		e2 = ast.NewErrorNode(err2, parsley.Pos(3), parsley.Pos(4))
+		n1 = ast.NewTerminalNode("TEST", "x", parsley.Pos(2), parsley.Pos(3))
+	})
+
+	It("returns nil if there are no error nodes", func() {
+		Expect(ast.Errors(n1)).To(BeNil())
+		Expect(ast.Errors(nil)).To(BeNil())
+	})
+
+	It("returns the error of an error node", func() {
+		Expect(ast.Errors(e1)).To(Equal([]parsley.Error{err1}))
+	})
+
+	It("returns the errors from all the children in order", func() {
+		node := ast.NewNonTerminalNode("TEST", []parsley.Node{
+			e1,
+			ast.NewNonTerminalNode("TEST", []parsley.Node{n1, e2}, nil),
+		}, nil)
+		Expect(ast.Errors(node)).To(Equal([]parsley.Error{err1, err2}))
+	})
+
+	It("returns the errors from the first node of a node list", func() {
+		Expect(ast.Errors(ast.NodeList([]parsley.Node{e1, e2}))).To(Equal([]parsley.Error{err1}))
+	})
+})
+
+var _ = Describe("NodeSpan", func() {
+	It("returns with the span of the node", func() {
+		node := ast.NewTerminalNode("TEST", "x", parsley.Pos(1), parsley.Pos(3))
+		Expect(ast.NodeSpan(node)).To(Equal(parsley.NewSpan(parsley.Pos(1), parsley.Pos(3))))
+	})
+
+	It("returns with the span of the first node of a node list", func() {
+		n1 := ast.NewTerminalNode("TEST", "x", parsley.Pos(1), parsley.Pos(3))
+		n2 := ast.NewTerminalNode("TEST", "y", parsley.Pos(1), parsley.Pos(4))
+		Expect(ast.NodeSpan(ast.NodeList([]parsley.Node{n1, n2}))).To(Equal(parsley.NewSpan(parsley.Pos(1), parsley.Pos(3))))
+	})
+
+	It("returns with an invalid span for an empty node list", func() {
+		Expect(ast.NodeSpan(ast.NodeList{})).To(Equal(parsley.NewSpan(parsley.NilPos, parsley.NilPos)))
+	})
+})
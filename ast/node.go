@@ -23,6 +23,9 @@ const NIL = "NIL"
 // EOF is the end of file token
 const EOF = "EOF"
 
+// ERROR is the token of the error nodes
+const ERROR = "ERROR"
+
 // TerminalNode is a leaf node in the AST
 type TerminalNode struct {
 	token     string
@@ -99,6 +102,58 @@ func (n NilNode) String() string {
 	return NIL
 }
 
+// ErrorNode represents a part of the input which couldn't be parsed
+// It's created when the parser recovers from a syntax error and evaluates to the error.
+type ErrorNode struct {
+	err       parsley.Error
+	pos       parsley.Pos
+	readerPos parsley.Pos
+}
+
+// NewErrorNode creates a new ErrorNode instance
+func NewErrorNode(err parsley.Error, pos parsley.Pos, readerPos parsley.Pos) *ErrorNode {
+	return &ErrorNode{
+		err:       err,
+		pos:       pos,
+		readerPos: readerPos,
+	}
+}
+
+// Token returns with ERROR
+func (e *ErrorNode) Token() string {
+	return ERROR
+}
+
+// Value returns with the error
+func (e *ErrorNode) Value(ctx interface{}) (interface{}, parsley.Error) {
+	return nil, e.err
+}
+
+// Err returns with the syntax error
+func (e *ErrorNode) Err() parsley.Error {
+	return e.err
+}
+
+// Pos returns the position
+func (e *ErrorNode) Pos() parsley.Pos {
+	return e.pos
+}
+
+// ReaderPos returns the position of the first character immediately after the skipped input
+func (e *ErrorNode) ReaderPos() parsley.Pos {
+	return e.readerPos
+}
+
+// SetReaderPos changes the reader position
+func (e *ErrorNode) SetReaderPos(f func(parsley.Pos) parsley.Pos) {
+	e.readerPos = f(e.readerPos)
+}
+
+// String returns with a string representation of the node
+func (e *ErrorNode) String() string {
+	return fmt.Sprintf("%s{%s, %d..%d}", ERROR, e.err, e.pos, e.readerPos)
+}
+
 // NonTerminalNode represents a branch node in the AST
 type NonTerminalNode struct {
 	token       string
@@ -100,6 +100,54 @@ var _ = Describe("NilNode", func() {
 	})
 })
 
+var _ = Describe("ErrorNode", func() {
+	var (
+		node      *ast.ErrorNode
+		err       parsley.Error = parsley.NewErrorf(parsley.Pos(2), "some error")
+		pos       parsley.Pos   = parsley.Pos(1)
+		readerPos parsley.Pos   = parsley.Pos(3)
+	)
+
+	JustBeforeEach(func() {
+		node = ast.NewErrorNode(err, pos, readerPos)
+	})
+
+	Describe("Methods", func() {
+		It("Token() should return with ERROR", func() {
+			Expect(node.Token()).To(Equal(ast.ERROR))
+		})
+
+		It("Value() should return with the error", func() {
+			nodeValue, nodeErr := node.Value(nil)
+			Expect(nodeValue).To(BeNil())
+			Expect(nodeErr).To(BeIdenticalTo(err))
+		})
+
+		It("Err() should return with the error", func() {
+			Expect(node.Err()).To(BeIdenticalTo(err))
+		})
+
+		It("Pos() should return with the position", func() {
+			Expect(node.Pos()).To(Equal(pos))
+		})
+
+		It("ReaderPos() should return with the reader position", func() {
+			Expect(node.ReaderPos()).To(Equal(readerPos))
+		})
+
+		It("SetReaderPos() should modify the reader position", func() {
+			node.SetReaderPos(func(pos parsley.Pos) parsley.Pos {
+				return parsley.Pos(pos + 1)
+			})
+			Expect(node.ReaderPos()).To(Equal(parsley.Pos(4)))
+		})
+
+		It("String() should return with a readable representation", func() {
+			Expect(node.String()).To(Equal("ERROR{some error, 1..3}"))
+		})
+	})
+})
+
 var _ = Describe("NonTerminalNode", func() {
 	var (
 		node            *ast.NonTerminalNode
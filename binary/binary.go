@@ -0,0 +1,20 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Package binary defines a byte-oriented input reader for parsing binary file formats and wire protocols.
+package binary
+
+import (
+	stdbinary "encoding/binary"
+)
+
+// ByteOrder defines how the multi-byte integers are encoded
+type ByteOrder = stdbinary.ByteOrder
+
+// Byte orders
+var (
+	BigEndian    ByteOrder = stdbinary.BigEndian
+	LittleEndian ByteOrder = stdbinary.LittleEndian
+)
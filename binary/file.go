@@ -0,0 +1,63 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package binary
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// File contains the contents of a binary file
+type File struct {
+	filename string
+	data     []byte
+	len      int
+	offset   int
+}
+
+// NewFile creates a new file object
+func NewFile(filename string, data []byte) *File {
+	return &File{
+		filename: filename,
+		data:     data,
+		len:      len(data),
+		offset:   1,
+	}
+}
+
+// ReadFile reads a file and creates a File object
+func ReadFile(filename string) (*File, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("can not read %s", filename)
+	}
+	return NewFile(filename, data), nil
+}
+
+// Len returns with the length of the file in bytes
+func (f *File) Len() int {
+	return f.len
+}
+
+// SetOffset set the offset of this file related to a file set
+func (f *File) SetOffset(offset int) {
+	f.offset = offset
+}
+
+// Position returns with a Position object for the given offset
+func (f *File) Position(pos int) parsley.Position {
+	if pos < 0 || pos > f.len {
+		return parsley.NilPosition
+	}
+	return NewPosition(f.filename, pos)
+}
+
+// Pos returns with a global offset in a file set
+func (f *File) Pos(pos int) parsley.Pos {
+	return parsley.Pos(f.offset + pos)
+}
@@ -0,0 +1,92 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package binary_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+var _ = Describe("File", func() {
+
+	var (
+		fs   *parsley.FileSet
+		f    *binary.File
+		data []byte
+	)
+
+	BeforeEach(func() {
+		data = []byte{0x01, 0x02, 0x03}
+	})
+
+	JustBeforeEach(func() {
+		f = binary.NewFile("testfile", data)
+		fs = parsley.NewFileSet(f)
+	})
+
+	It("should implement the parsley.File interface", func() {
+		var _ parsley.File = &binary.File{}
+	})
+
+	Describe("Position()", func() {
+		It("should return with the position for an offset", func() {
+			Expect(f.Position(0)).To(Equal(binary.NewPosition("testfile", 0)))
+			Expect(f.Position(3)).To(Equal(binary.NewPosition("testfile", 3)))
+		})
+
+		It("should return nil position for an invalid offset", func() {
+			Expect(f.Position(4)).To(Equal(parsley.NilPosition))
+		})
+	})
+
+	Describe("Len()", func() {
+		It("should return the data length", func() {
+			Expect(f.Len()).To(Equal(3))
+		})
+	})
+
+	Describe("Pos()", func() {
+		It("should return with a global position", func() {
+			pos := f.Pos(2)
+			Expect(pos).To(Equal(parsley.Pos(3)))
+			Expect(fs.Position(pos)).To(Equal(binary.NewPosition("testfile", 2)))
+		})
+	})
+
+	Context("ReadFile", func() {
+		var tmpDir string
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "parsley-test-")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		It("should read an existing file", func() {
+			filename := filepath.Join(tmpDir, "testfile")
+			ioutil.WriteFile(filename, []byte{0xca, 0xfe}, 0600)
+			f, err := binary.ReadFile(filename)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(f.Len()).To(Equal(2))
+		})
+
+		It("should return an error for a non-existing file", func() {
+			_, err := binary.ReadFile("/tmp/non-existing-filename")
+			Expect(err).To(MatchError("can not read /tmp/non-existing-filename"))
+		})
+	})
+})
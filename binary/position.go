@@ -0,0 +1,32 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package binary
+
+import (
+	"fmt"
+)
+
+// Position is a binary file position
+type Position struct {
+	Filename string
+	Offset   int
+}
+
+// NewPosition creates a new binary position
+func NewPosition(filename string, offset int) *Position {
+	return &Position{
+		Filename: filename,
+		Offset:   offset,
+	}
+}
+
+func (pos Position) String() string {
+	if pos.Filename != "" {
+		return fmt.Sprintf("%s:0x%x", pos.Filename, pos.Offset)
+	}
+
+	return fmt.Sprintf("0x%x", pos.Offset)
+}
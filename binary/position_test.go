@@ -0,0 +1,28 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package binary_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+var _ = Describe("Position", func() {
+	It("should implement the parsley.Position interface", func() {
+		var _ parsley.Position = binary.Position{}
+	})
+
+	It("should return with the offset in hexadecimal format", func() {
+		Expect(binary.NewPosition("testfile", 26).String()).To(Equal("testfile:0x1a"))
+	})
+
+	It("should not contain the filename if empty", func() {
+		Expect(binary.NewPosition("", 26).String()).To(Equal("0x1a"))
+	})
+})
@@ -0,0 +1,99 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package binary
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Reader defines a binary input reader
+type Reader struct {
+	file *File
+}
+
+// NewReader creates a new reader instance
+func NewReader(file *File) *Reader {
+	return &Reader{
+		file: file,
+	}
+}
+
+// NextByte reads the next byte
+func (r *Reader) NextByte(pos parsley.Pos) (parsley.Pos, byte, bool) {
+	cur := int(pos) - r.file.offset
+	if cur >= r.file.len {
+		return pos, 0, false
+	}
+	return r.file.Pos(cur + 1), r.file.data[cur], true
+}
+
+// ReadBytes reads the next n bytes
+// If there are less than n bytes remaining it returns with nil.
+func (r *Reader) ReadBytes(pos parsley.Pos, n int) (parsley.Pos, []byte) {
+	if n <= 0 {
+		panic("ReadBytes() should be called with a positive length")
+	}
+
+	cur := int(pos) - r.file.offset
+	if n > r.file.len-cur {
+		return pos, nil
+	}
+
+	return r.file.Pos(cur + n), r.file.data[cur : cur+n]
+}
+
+// MatchBytes matches the given byte sequence
+func (r *Reader) MatchBytes(pos parsley.Pos, b []byte) (parsley.Pos, bool) {
+	if len(b) == 0 {
+		panic("MatchBytes() should not be called with an empty byte slice")
+	}
+
+	cur := int(pos) - r.file.offset
+	if len(b) > r.file.len-cur {
+		return pos, false
+	}
+
+	if bytes.HasPrefix(r.file.data[cur:], b) {
+		return r.file.Pos(cur + len(b)), true
+	}
+	return pos, false
+}
+
+// Peek returns with the remaining data without moving the position
+// The returned slice must not be modified.
+func (r *Reader) Peek(pos parsley.Pos) []byte {
+	cur := int(pos) - r.file.offset
+	if cur >= r.file.len {
+		return nil
+	}
+	return r.file.data[cur:]
+}
+
+// Remaining returns with the remaining byte count
+func (r *Reader) Remaining(pos parsley.Pos) int {
+	return r.file.len - (int(pos) - r.file.offset)
+}
+
+// IsEOF returns true if we reached the end of the buffer
+func (r *Reader) IsEOF(pos parsley.Pos) bool {
+	return int(pos)-r.file.offset >= r.file.len
+}
+
+// Describe returns with the next byte in hexadecimal format or "end of input" if we reached the end of the buffer
+func (r *Reader) Describe(pos parsley.Pos) string {
+	if r.IsEOF(pos) {
+		return "end of input"
+	}
+	return fmt.Sprintf("0x%02x", r.file.data[int(pos)-r.file.offset])
+}
+
+// Pos returns with the global position for the given cursor
+func (r *Reader) Pos(cur int) parsley.Pos {
+	return r.file.Pos(cur)
+}
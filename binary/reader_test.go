@@ -0,0 +1,114 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package binary_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+var _ = Describe("Reader", func() {
+
+	var (
+		f *binary.File
+		r *binary.Reader
+	)
+
+	BeforeEach(func() {
+		f = binary.NewFile("testfile", []byte{0x01, 0x02, 0x03, 0x04})
+		r = binary.NewReader(f)
+	})
+
+	It("should implement the parsley.Reader and parsley.Describer interfaces", func() {
+		var _ parsley.Reader = r
+		var _ parsley.Describer = r
+	})
+
+	Describe("NextByte()", func() {
+		It("should read the next byte", func() {
+			pos, b, ok := r.NextByte(f.Pos(1))
+			Expect(ok).To(BeTrue())
+			Expect(b).To(Equal(byte(0x02)))
+			Expect(pos).To(Equal(f.Pos(2)))
+		})
+
+		It("should return false at the end of the input", func() {
+			pos, _, ok := r.NextByte(f.Pos(4))
+			Expect(ok).To(BeFalse())
+			Expect(pos).To(Equal(f.Pos(4)))
+		})
+	})
+
+	Describe("ReadBytes()", func() {
+		It("should read the given number of bytes", func() {
+			pos, b := r.ReadBytes(f.Pos(1), 2)
+			Expect(b).To(Equal([]byte{0x02, 0x03}))
+			Expect(pos).To(Equal(f.Pos(3)))
+		})
+
+		It("should return nil if there are not enough bytes", func() {
+			pos, b := r.ReadBytes(f.Pos(3), 2)
+			Expect(b).To(BeNil())
+			Expect(pos).To(Equal(f.Pos(3)))
+		})
+
+		It("should panic if called with zero length", func() {
+			Expect(func() { r.ReadBytes(f.Pos(0), 0) }).To(Panic())
+		})
+	})
+
+	Describe("MatchBytes()", func() {
+		It("should match the byte sequence", func() {
+			pos, ok := r.MatchBytes(f.Pos(1), []byte{0x02, 0x03})
+			Expect(ok).To(BeTrue())
+			Expect(pos).To(Equal(f.Pos(3)))
+		})
+
+		It("should not match a different byte sequence", func() {
+			pos, ok := r.MatchBytes(f.Pos(1), []byte{0x02, 0x04})
+			Expect(ok).To(BeFalse())
+			Expect(pos).To(Equal(f.Pos(1)))
+		})
+
+		It("should not match if there are not enough bytes", func() {
+			_, ok := r.MatchBytes(f.Pos(3), []byte{0x04, 0x05})
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should panic if called with an empty slice", func() {
+			Expect(func() { r.MatchBytes(f.Pos(0), nil) }).To(Panic())
+		})
+	})
+
+	Describe("Peek()", func() {
+		It("should return with the remaining data", func() {
+			Expect(r.Peek(f.Pos(2))).To(Equal([]byte{0x03, 0x04}))
+			Expect(r.Peek(f.Pos(4))).To(BeNil())
+		})
+	})
+
+	Describe("Remaining() and IsEOF()", func() {
+		It("should return with the remaining bytes", func() {
+			Expect(r.Remaining(f.Pos(1))).To(Equal(3))
+			Expect(r.IsEOF(f.Pos(1))).To(BeFalse())
+			Expect(r.Remaining(f.Pos(4))).To(Equal(0))
+			Expect(r.IsEOF(f.Pos(4))).To(BeTrue())
+		})
+	})
+
+	Describe("Describe()", func() {
+		It("should return with the next byte", func() {
+			Expect(r.Describe(f.Pos(1))).To(Equal("0x02"))
+		})
+
+		It("should return end of input at the end", func() {
+			Expect(r.Describe(f.Pos(4))).To(Equal("end of input"))
+		})
+	})
+})
@@ -0,0 +1,55 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal
+
+import (
+	"fmt"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// BitFields matches bit fields with the given widths, starting from the most significant bit of the first byte
+// The total width has to be a multiple of 8 and at most 64 bits. The value of the node is a []uint64 slice
+// containing the value of every field.
+func BitFields(widths ...int) *parser.NamedFunc {
+	total := 0
+	for _, w := range widths {
+		if w <= 0 || w > 64 {
+			panic("BitFields() should be called with widths between 1 and 64")
+		}
+		total += w
+	}
+	if total == 0 || total%8 != 0 || total > 64 {
+		panic("the total width of the bit fields should be a multiple of 8 and at most 64")
+	}
+	size := total / 8
+
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		br := r.(*binary.Reader)
+		readerPos, b := br.ReadBytes(pos, size)
+		if b == nil {
+			return nil, nil, data.EmptyIntSet
+		}
+
+		var bits uint64
+		for _, v := range b {
+			bits = bits<<8 | uint64(v)
+		}
+
+		values := make([]uint64, len(widths))
+		shift := uint(total)
+		for i, w := range widths {
+			shift -= uint(w)
+			values[i] = bits >> shift & (1<<uint(w) - 1)
+		}
+
+		return ast.NewTerminalNode("BITS", values, pos, readerPos), nil, data.EmptyIntSet
+	}).WithName(fmt.Sprintf("%d-bit bit fields", total))
+}
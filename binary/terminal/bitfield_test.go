@@ -0,0 +1,53 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/binary/terminal"
+	"github.com/sniperkit/snk.fork.parsley/data"
+)
+
+var _ = Describe("BitFields", func() {
+
+	It("should have a name", func() {
+		Expect(terminal.BitFields(4, 4).Name()).To(Equal("8-bit bit fields"))
+	})
+
+	It("should extract the fields starting from the most significant bit", func() {
+		// 1011 0 010 | 1111 0000
+		f := binary.NewFile("testfile", []byte{0xb2, 0xf0})
+		res, err, _ := terminal.BitFields(4, 1, 3, 8).Parse(nil, data.EmptyIntMap, binary.NewReader(f), f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		node := res.(*ast.TerminalNode)
+		Expect(node.Token()).To(Equal("BITS"))
+		Expect(node.Value(nil)).To(Equal([]uint64{0xb, 0, 2, 0xf0}))
+		Expect(node.ReaderPos()).To(Equal(f.Pos(2)))
+	})
+
+	It("should handle a 64-bit field", func() {
+		f := binary.NewFile("testfile", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+		res, _, _ := terminal.BitFields(64).Parse(nil, data.EmptyIntMap, binary.NewReader(f), f.Pos(0))
+		Expect(res.(*ast.TerminalNode).Value(nil)).To(Equal([]uint64{0xffffffffffffffff}))
+	})
+
+	It("should not match if there are not enough bytes", func() {
+		f := binary.NewFile("testfile", []byte{0x01})
+		res, err, _ := terminal.BitFields(8, 8).Parse(nil, data.EmptyIntMap, binary.NewReader(f), f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("should panic if the total width is invalid", func() {
+		Expect(func() { terminal.BitFields(3, 4) }).To(Panic())
+		Expect(func() { terminal.BitFields(64, 8) }).To(Panic())
+		Expect(func() { terminal.BitFields(0, 8) }).To(Panic())
+	})
+})
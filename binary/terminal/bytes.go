@@ -0,0 +1,59 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal
+
+import (
+	"fmt"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Byte matches the given byte
+func Byte(b byte) *parser.NamedFunc {
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		br := r.(*binary.Reader)
+		if readerPos, next, ok := br.NextByte(pos); ok && next == b {
+			return ast.NewTerminalNode("BYTE", b, pos, readerPos), nil, data.EmptyIntSet
+		}
+		return nil, nil, data.EmptyIntSet
+	}).WithName(fmt.Sprintf("0x%02x", b))
+}
+
+// Bytes matches the given byte sequence (e.g. a magic number)
+func Bytes(b []byte) *parser.NamedFunc {
+	if len(b) == 0 {
+		panic("Bytes() should not be called with an empty byte slice")
+	}
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		br := r.(*binary.Reader)
+		if readerPos, ok := br.MatchBytes(pos, b); ok {
+			return ast.NewTerminalNode("BYTES", b, pos, readerPos), nil, data.EmptyIntSet
+		}
+		return nil, nil, data.EmptyIntSet
+	}).WithName(fmt.Sprintf("0x% x", b))
+}
+
+// Raw matches the next n bytes and returns them without any conversion
+// If n is zero then it returns with an empty byte slice without reading the input.
+func Raw(n int) *parser.NamedFunc {
+	if n < 0 {
+		panic("Raw() should not be called with a negative length")
+	}
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		if n == 0 {
+			return ast.NewTerminalNode("RAW", []byte{}, pos, pos), nil, data.EmptyIntSet
+		}
+		br := r.(*binary.Reader)
+		if readerPos, b := br.ReadBytes(pos, n); b != nil {
+			return ast.NewTerminalNode("RAW", b, pos, readerPos), nil, data.EmptyIntSet
+		}
+		return nil, nil, data.EmptyIntSet
+	}).WithName(fmt.Sprintf("%d bytes", n))
+}
@@ -0,0 +1,110 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/binary/terminal"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+var _ = Describe("Byte literals", func() {
+
+	var (
+		f *binary.File
+		r *binary.Reader
+	)
+
+	BeforeEach(func() {
+		f = binary.NewFile("testfile", []byte{0x89, 'P', 'N', 'G'})
+		r = binary.NewReader(f)
+	})
+
+	parse := func(p parsley.Parser, pos int) (*ast.TerminalNode, parsley.Error) {
+		res, err, cp := p.Parse(nil, data.EmptyIntMap, r, f.Pos(pos))
+		Expect(cp).To(Equal(data.EmptyIntSet))
+		if res == nil {
+			return nil, err
+		}
+		return res.(*ast.TerminalNode), err
+	}
+
+	Describe("Byte()", func() {
+		It("should have a name", func() {
+			Expect(terminal.Byte(0x89).Name()).To(Equal("0x89"))
+		})
+
+		It("should match the byte", func() {
+			node, err := parse(terminal.Byte(0x89), 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(node.Token()).To(Equal("BYTE"))
+			Expect(node.Value(nil)).To(Equal(byte(0x89)))
+			Expect(node.ReaderPos()).To(Equal(f.Pos(1)))
+		})
+
+		It("should not match a different byte", func() {
+			node, err := parse(terminal.Byte(0x88), 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(node).To(BeNil())
+		})
+	})
+
+	Describe("Bytes()", func() {
+		It("should have a name", func() {
+			Expect(terminal.Bytes([]byte{0xca, 0xfe}).Name()).To(Equal("0xca fe"))
+		})
+
+		It("should match the byte sequence", func() {
+			node, err := parse(terminal.Bytes([]byte("PNG")), 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(node.Token()).To(Equal("BYTES"))
+			Expect(node.Value(nil)).To(Equal([]byte("PNG")))
+			Expect(node.ReaderPos()).To(Equal(f.Pos(4)))
+		})
+
+		It("should not match a different sequence", func() {
+			node, err := parse(terminal.Bytes([]byte("PNx")), 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(node).To(BeNil())
+		})
+
+		It("should panic if called with an empty slice", func() {
+			Expect(func() { terminal.Bytes(nil) }).To(Panic())
+		})
+	})
+
+	Describe("Raw()", func() {
+		It("should match the given number of bytes", func() {
+			node, err := parse(terminal.Raw(2), 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(node.Token()).To(Equal("RAW"))
+			Expect(node.Value(nil)).To(Equal([]byte("PN")))
+			Expect(node.ReaderPos()).To(Equal(f.Pos(3)))
+		})
+
+		It("should not match if there are not enough bytes", func() {
+			node, err := parse(terminal.Raw(4), 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(node).To(BeNil())
+		})
+
+		It("should return an empty match for zero length", func() {
+			node, err := parse(terminal.Raw(0), 4)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(node.Value(nil)).To(Equal([]byte{}))
+			Expect(node.ReaderPos()).To(Equal(f.Pos(4)))
+		})
+
+		It("should panic if called with a negative length", func() {
+			Expect(func() { terminal.Raw(-1) }).To(Panic())
+		})
+	})
+})
@@ -0,0 +1,64 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal
+
+import (
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Uint8 matches an unsigned 8-bit integer
+func Uint8() *parser.NamedFunc {
+	return fixedWidthInteger("uint8", "UINT8", 1, func(b []byte) interface{} { return b[0] })
+}
+
+// Int8 matches a signed 8-bit integer
+func Int8() *parser.NamedFunc {
+	return fixedWidthInteger("int8", "INT8", 1, func(b []byte) interface{} { return int8(b[0]) })
+}
+
+// Uint16 matches an unsigned 16-bit integer with the given byte order
+func Uint16(order binary.ByteOrder) *parser.NamedFunc {
+	return fixedWidthInteger("uint16", "UINT16", 2, func(b []byte) interface{} { return order.Uint16(b) })
+}
+
+// Int16 matches a signed 16-bit integer with the given byte order
+func Int16(order binary.ByteOrder) *parser.NamedFunc {
+	return fixedWidthInteger("int16", "INT16", 2, func(b []byte) interface{} { return int16(order.Uint16(b)) })
+}
+
+// Uint32 matches an unsigned 32-bit integer with the given byte order
+func Uint32(order binary.ByteOrder) *parser.NamedFunc {
+	return fixedWidthInteger("uint32", "UINT32", 4, func(b []byte) interface{} { return order.Uint32(b) })
+}
+
+// Int32 matches a signed 32-bit integer with the given byte order
+func Int32(order binary.ByteOrder) *parser.NamedFunc {
+	return fixedWidthInteger("int32", "INT32", 4, func(b []byte) interface{} { return int32(order.Uint32(b)) })
+}
+
+// Uint64 matches an unsigned 64-bit integer with the given byte order
+func Uint64(order binary.ByteOrder) *parser.NamedFunc {
+	return fixedWidthInteger("uint64", "UINT64", 8, func(b []byte) interface{} { return order.Uint64(b) })
+}
+
+// Int64 matches a signed 64-bit integer with the given byte order
+func Int64(order binary.ByteOrder) *parser.NamedFunc {
+	return fixedWidthInteger("int64", "INT64", 8, func(b []byte) interface{} { return int64(order.Uint64(b)) })
+}
+
+func fixedWidthInteger(name string, token string, size int, value func([]byte) interface{}) *parser.NamedFunc {
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		br := r.(*binary.Reader)
+		if readerPos, b := br.ReadBytes(pos, size); b != nil {
+			return ast.NewTerminalNode(token, value(b), pos, readerPos), nil, data.EmptyIntSet
+		}
+		return nil, nil, data.EmptyIntSet
+	}).WithName(name)
+}
@@ -0,0 +1,98 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/binary/terminal"
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Let's define a parser for a simple header with a magic number, a version and a little-endian length.
+func ExampleUint16() {
+	p := combinator.Seq("HEADER", "header",
+		terminal.Bytes([]byte("PSL")),
+		terminal.Uint8(),
+		terminal.Uint16(binary.LittleEndian),
+	).Bind(ast.InterpreterFunc(func(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+		values := make([]interface{}, len(nodes))
+		for i, node := range nodes {
+			values[i], _ = node.Value(ctx)
+		}
+		return values, nil
+	}))
+	r := binary.NewReader(binary.NewFile("example.bin", []byte{'P', 'S', 'L', 0x01, 0x34, 0x12}))
+	value, _ := parsley.Evaluate(parser.NewHistory(), r, combinator.Sentence(p), nil)
+	for _, v := range value.([]interface{}) {
+		fmt.Printf("%T %v\n", v, v)
+	}
+	// Output:
+	// []uint8 [80 83 76]
+	// uint8 1
+	// uint16 4660
+}
+
+var _ = Describe("Fixed-width integers", func() {
+
+	DescribeTable("should match",
+		func(p parsley.Parser, input []byte, token string, value interface{}, endPos int) {
+			f := binary.NewFile("testfile", input)
+			r := binary.NewReader(f)
+			res, err, curtailingParsers := p.Parse(nil, data.EmptyIntMap, r, f.Pos(0))
+			Expect(curtailingParsers).To(Equal(data.EmptyIntSet))
+			Expect(err).ToNot(HaveOccurred())
+			node := res.(*ast.TerminalNode)
+			Expect(node.Token()).To(Equal(token))
+			Expect(node.Value(nil)).To(Equal(value))
+			Expect(node.Pos()).To(Equal(f.Pos(0)))
+			Expect(node.ReaderPos()).To(Equal(f.Pos(endPos)))
+		},
+		Entry("uint8", terminal.Uint8(), []byte{0xff, 0x01}, "UINT8", uint8(0xff), 1),
+		Entry("int8", terminal.Int8(), []byte{0xff}, "INT8", int8(-1), 1),
+		Entry("uint16 BE", terminal.Uint16(binary.BigEndian), []byte{0x12, 0x34}, "UINT16", uint16(0x1234), 2),
+		Entry("uint16 LE", terminal.Uint16(binary.LittleEndian), []byte{0x12, 0x34}, "UINT16", uint16(0x3412), 2),
+		Entry("int16 BE", terminal.Int16(binary.BigEndian), []byte{0xff, 0xfe}, "INT16", int16(-2), 2),
+		Entry("int16 LE", terminal.Int16(binary.LittleEndian), []byte{0xfe, 0xff}, "INT16", int16(-2), 2),
+		Entry("uint32 BE", terminal.Uint32(binary.BigEndian), []byte{0x12, 0x34, 0x56, 0x78}, "UINT32", uint32(0x12345678), 4),
+		Entry("uint32 LE", terminal.Uint32(binary.LittleEndian), []byte{0x12, 0x34, 0x56, 0x78}, "UINT32", uint32(0x78563412), 4),
+		Entry("int32 BE", terminal.Int32(binary.BigEndian), []byte{0xff, 0xff, 0xff, 0xfd}, "INT32", int32(-3), 4),
+		Entry("int32 LE", terminal.Int32(binary.LittleEndian), []byte{0xfd, 0xff, 0xff, 0xff}, "INT32", int32(-3), 4),
+		Entry("uint64 BE", terminal.Uint64(binary.BigEndian), []byte{1, 2, 3, 4, 5, 6, 7, 8}, "UINT64", uint64(0x0102030405060708), 8),
+		Entry("uint64 LE", terminal.Uint64(binary.LittleEndian), []byte{1, 2, 3, 4, 5, 6, 7, 8}, "UINT64", uint64(0x0807060504030201), 8),
+		Entry("int64 BE", terminal.Int64(binary.BigEndian), []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfc}, "INT64", int64(-4), 8),
+		Entry("int64 LE", terminal.Int64(binary.LittleEndian), []byte{0xfc, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "INT64", int64(-4), 8),
+	)
+
+	DescribeTable("should not match if there are not enough bytes",
+		func(p parsley.Parser, input []byte) {
+			f := binary.NewFile("testfile", input)
+			r := binary.NewReader(f)
+			res, err, curtailingParsers := p.Parse(nil, data.EmptyIntMap, r, f.Pos(0))
+			Expect(curtailingParsers).To(Equal(data.EmptyIntSet))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+		},
+		Entry("uint8", terminal.Uint8(), []byte{}),
+		Entry("uint16", terminal.Uint16(binary.BigEndian), []byte{0x01}),
+		Entry("int32", terminal.Int32(binary.BigEndian), []byte{0x01, 0x02, 0x03}),
+		Entry("uint64", terminal.Uint64(binary.LittleEndian), []byte{0x01, 0x02, 0x03, 0x04}),
+	)
+
+	It("should have names", func() {
+		Expect(terminal.Uint16(binary.BigEndian).Name()).To(Equal("uint16"))
+		Expect(terminal.Int64(binary.LittleEndian).Name()).To(Equal("int64"))
+	})
+})
@@ -0,0 +1,7 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Package terminal contains basic terminal parsers for binary parsing
+package terminal
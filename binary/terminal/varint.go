@@ -0,0 +1,64 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal
+
+import (
+	stdbinary "encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+var errVarintOverflow = errors.New("varint overflows a 64-bit integer")
+
+// Uvarint matches an unsigned variable-length integer (as encoded by encoding/binary.PutUvarint)
+func Uvarint() *parser.NamedFunc {
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		br := r.(*binary.Reader)
+		b := br.Peek(pos)
+		if b == nil {
+			return nil, nil, data.EmptyIntSet
+		}
+		value, n := stdbinary.Uvarint(b)
+		if err := varintError(br, pos, b, n, "uvarint"); err != nil {
+			return nil, err, data.EmptyIntSet
+		}
+		return ast.NewTerminalNode("UVARINT", value, pos, pos+parsley.Pos(n)), nil, data.EmptyIntSet
+	}).WithName("uvarint")
+}
+
+// Varint matches a signed, zig-zag encoded variable-length integer (as encoded by encoding/binary.PutVarint)
+func Varint() *parser.NamedFunc {
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		br := r.(*binary.Reader)
+		b := br.Peek(pos)
+		if b == nil {
+			return nil, nil, data.EmptyIntSet
+		}
+		value, n := stdbinary.Varint(b)
+		if err := varintError(br, pos, b, n, "varint"); err != nil {
+			return nil, err, data.EmptyIntSet
+		}
+		return ast.NewTerminalNode("VARINT", value, pos, pos+parsley.Pos(n)), nil, data.EmptyIntSet
+	}).WithName("varint")
+}
+
+// varintError returns with an error if the varint is truncated (n == 0) or it overflows (n < 0)
+func varintError(r *binary.Reader, pos parsley.Pos, b []byte, n int, name string) parsley.Error {
+	switch {
+	case n == 0:
+		return parsley.DescribeError(r, parsley.NewExpectedError(pos+parsley.Pos(len(b)), name+" continuation byte"))
+	case n < 0:
+		return parsley.NewInvalidValueError(pos, pos+parsley.Pos(-n), name, fmt.Sprintf("% x", b[:-n]), errVarintOverflow)
+	default:
+		return nil
+	}
+}
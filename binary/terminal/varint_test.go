@@ -0,0 +1,71 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal_test
+
+import (
+	"errors"
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	"github.com/sniperkit/snk.fork.parsley/binary/terminal"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+var _ = Describe("Varints", func() {
+
+	DescribeTable("should match",
+		func(p parsley.Parser, input []byte, token string, value interface{}, endPos int) {
+			f := binary.NewFile("testfile", input)
+			r := binary.NewReader(f)
+			res, err, _ := p.Parse(nil, data.EmptyIntMap, r, f.Pos(0))
+			Expect(err).ToNot(HaveOccurred())
+			node := res.(*ast.TerminalNode)
+			Expect(node.Token()).To(Equal(token))
+			Expect(node.Value(nil)).To(Equal(value))
+			Expect(node.ReaderPos()).To(Equal(f.Pos(endPos)))
+		},
+		Entry("uvarint one byte", terminal.Uvarint(), []byte{0x05, 0xff}, "UVARINT", uint64(5), 1),
+		Entry("uvarint two bytes", terminal.Uvarint(), []byte{0xac, 0x02}, "UVARINT", uint64(300), 2),
+		Entry("varint positive", terminal.Varint(), []byte{0x04}, "VARINT", int64(2), 1),
+		Entry("varint negative", terminal.Varint(), []byte{0x03}, "VARINT", int64(-2), 1),
+		Entry("varint two bytes", terminal.Varint(), []byte{0xd7, 0x04}, "VARINT", int64(-300), 2),
+	)
+
+	It("should not match at the end of the input", func() {
+		f := binary.NewFile("testfile", []byte{})
+		res, err, _ := terminal.Uvarint().Parse(nil, data.EmptyIntMap, binary.NewReader(f), f.Pos(0))
+		Expect(res).To(BeNil())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should return an unexpected EOF error for a truncated varint", func() {
+		f := binary.NewFile("testfile", []byte{0x80, 0x80})
+		res, err, _ := terminal.Uvarint().Parse(nil, data.EmptyIntMap, binary.NewReader(f), f.Pos(0))
+		Expect(res).To(BeNil())
+		Expect(err).To(MatchError("unexpected end of input, was expecting uvarint continuation byte"))
+		Expect(err.Pos()).To(Equal(f.Pos(2)))
+		Expect(errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue())
+	})
+
+	It("should return an invalid value error if the varint overflows", func() {
+		input := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02}
+		f := binary.NewFile("testfile", input)
+		res, err, _ := terminal.Uvarint().Parse(nil, data.EmptyIntMap, binary.NewReader(f), f.Pos(0))
+		Expect(res).To(BeNil())
+		Expect(err).To(MatchError("invalid uvarint value encountered"))
+		Expect(parsley.EndPos(err)).To(Equal(f.Pos(10)))
+
+		var invalidErr *parsley.InvalidValueError
+		Expect(errors.As(err, &invalidErr)).To(BeTrue())
+		Expect(invalidErr.Type).To(Equal("uvarint"))
+	})
+})
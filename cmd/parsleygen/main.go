@@ -0,0 +1,68 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// The parsleygen command generates Go parsers from grammar definitions (see the grammar package for the syntax).
+//
+// It's intended to be used with go generate:
+//
+//	//go:generate go run github.com/sniperkit/snk.fork.parsley/cmd/parsleygen -o calc_parser.go calc.ebnf
+//
+// The package name is taken from the $GOPACKAGE environment variable set by go generate if it's not set explicitly.
+// By default the output file is the grammar file's name with a "_parser.go" suffix.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sniperkit/snk.fork.parsley/grammar"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+var wsModes = map[string]text.WsMode{
+	"none":     text.WsNone,
+	"spaces":   text.WsSpaces,
+	"spacesnl": text.WsSpacesNl,
+}
+
+func main() {
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "the package name of the generated file")
+	output := flag.String("o", "", "the output file")
+	ws := flag.String("ws", "spacesnl", "the whitespaces to skip before the terminals: none, spaces or spacesnl")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: parsleygen [options] <grammar file>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *pkg == "" {
+		exit("the package name must be set with -package")
+	}
+	wsMode, ok := wsModes[*ws]
+	if !ok {
+		exit(fmt.Sprintf("invalid whitespace mode: %s", *ws))
+	}
+
+	filename := flag.Arg(0)
+	if *output == "" {
+		*output = strings.TrimSuffix(filename, filepath.Ext(filename)) + "_parser.go"
+	}
+
+	if err := grammar.NewGenerator(*pkg).WithWsMode(wsMode).GenerateFile(filename, *output); err != nil {
+		exit(err.Error())
+	}
+}
+
+func exit(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
@@ -29,14 +29,21 @@ func Any(name string, parsers ...parsley.Parser) *parser.NamedFunc {
 		var res parsley.Node
 		var err parsley.Error
 		for _, p := range parsers {
-			h.RegisterCall()
-			res2, err2, cp2 := p.Parse(h, leftRecCtx, r, pos)
+			if err := h.RegisterCall(pos); err != nil {
+				return nil, err, cp
+			}
+			res2, err2, cp2 := parsley.Call(h, p, leftRecCtx, r, pos)
 			cp = cp.Union(cp2)
+			if err2 != nil && parsley.IsFatal(err2) {
+				return nil, err2, cp
+			}
 			res = ast.AppendNode(res, res2)
-			if err2 != nil && (err == nil || err2.Pos() >= err.Pos()) {
-				err = err2
+			if err2 != nil {
+				err = parsley.MergeErrors(err, err2)
+			} else if res2 == nil {
+				err = expect(err, pos, p)
 			}
 		}
 		return res, err, cp
-	}).WithName(name)
+	}).WithName(name).WithChildren(parsley.KindAny, parsers...)
 }
@@ -161,10 +161,47 @@ var _ = Describe("Any", func() {
 			})
 		})
 
+		Context("when the history returns with an error", func() {
+			var historyErr parsley.Error
+
+			BeforeEach(func() {
+				historyErr = parsley.NewFatalError(parsley.Pos(1), fmt.Errorf("aborted"))
+				h.RegisterCallReturnsOnCall(1, historyErr)
+			})
+
+			It("should register the call with the position", func() {
+				Expect(h.RegisterCallCallCount()).To(Equal(2))
+				Expect(h.RegisterCallArgsForCall(0)).To(Equal(pos))
+			})
+
+			It("should not call the remaining parsers and return with the error", func() {
+				Expect(res).To(BeNil())
+				Expect(parserErr).To(BeIdenticalTo(historyErr))
+				Expect(p2.ParseCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a parser returns with a fatal error", func() {
+			BeforeEach(func() {
+				p1Res = n1
+				p1Err = parsley.NewFatalError(parsley.Pos(1), fmt.Errorf("fatal"))
+			})
+
+			It("should not call the remaining parsers and return with the error", func() {
+				Expect(res).To(BeNil())
+				Expect(parserErr).To(BeIdenticalTo(p1Err))
+				Expect(p2.ParseCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when no parsers match", func() {
 			It("should return nil", func() {
 				Expect(res).To(BeNil())
 			})
+
+			It("should return with an error listing all the parsers", func() {
+				Expect(parserErr).To(Equal(parsley.NewExpectedError(pos, "p1", "p2")))
+			})
 		})
 
 		Context("when one parser matches", func() {
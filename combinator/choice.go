@@ -27,16 +27,23 @@ func Choice(name string, parsers ...parsley.Parser) *parser.NamedFunc {
 		cp := data.EmptyIntSet
 		var err parsley.Error
 		for _, p := range parsers {
-			h.RegisterCall()
-			node, err2, cp2 := p.Parse(h, leftRecCtx, r, pos)
+			if err := h.RegisterCall(pos); err != nil {
+				return nil, err, cp
+			}
+			node, err2, cp2 := parsley.Call(h, p, leftRecCtx, r, pos)
 			cp = cp.Union(cp2)
-			if err2 != nil && (err == nil || err2.Pos() >= err.Pos()) {
-				err = err2
+			if err2 != nil && parsley.IsFatal(err2) {
+				return nil, err2, cp
+			}
+			if err2 != nil {
+				err = parsley.MergeErrors(err, err2)
+			} else if node == nil {
+				err = expect(err, pos, p)
 			}
 			if node != nil {
 				return node, err, cp
 			}
 		}
 		return nil, err, cp
-	}).WithName(name)
+	}).WithName(name).WithChildren(parsley.KindChoice, parsers...)
 }
@@ -0,0 +1,32 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package combinator
+
+import (
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Count applies the parser exactly n times (e.g. for counted arrays)
+// If n is zero then it returns with an empty match. It can be used with NewDependent if the count is read from the input.
+func Count(token string, name string, n int, p parsley.Parser) *Recursive {
+	if n < 0 {
+		panic("Count() should not be called with a negative count")
+	}
+	namef := p.Name
+	if name != "" {
+		namef = func() string { return name }
+	}
+	lookup := func(i int) parsley.Parser {
+		if i < n {
+			return p
+		}
+		return nil
+	}
+	lenCheck := func(len int) bool {
+		return len == n
+	}
+	return NewRecursive(token, namef, lookup, lenCheck).WithChildren(parsley.KindCount, p).WithCount(n)
+}
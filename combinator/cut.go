@@ -0,0 +1,74 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package combinator
+
+import (
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+var cutParser = parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+	parsley.Commit(h, pos)
+	return ast.NilNode(pos), nil, data.EmptyIntSet
+}).WithName("").WithChildren(parsley.KindEmpty)
+
+// Cut commits the current alternative when used in a sequence (Seq or SeqTry)
+// The cut is removed from the sequence, so it doesn't add a node to the result. If any of the parsers after the cut
+// doesn't match then the sequence returns with a fatal error at the position of the failure, so the parsing will
+// stop and no other alternative will be tried. When the sequence reaches the cut the history is committed at the
+// current position (see parsley.Committer), so it may drop the memoized results before the cut.
+//
+// The cut should be placed after a parser which consumes some input, e.g. a keyword, and it must be followed by a
+// parser, otherwise the sequence panics when it's created. When used outside of a sequence it always matches with an
+// empty result and commits the history.
+func Cut() parsley.Parser {
+	return cutParser
+}
+
+// removeCuts removes the cuts from the parsers and wraps all the parsers after the first cut with committed
+// It panics if there is no parser after the first cut.
+func removeCuts(parsers []parsley.Parser) []parsley.Parser {
+	res := make([]parsley.Parser, 0, len(parsers))
+	cutIndex := -1
+	for _, p := range parsers {
+		if p == cutParser {
+			if cutIndex == -1 {
+				cutIndex = len(res)
+			}
+			continue
+		}
+		if cutIndex >= 0 {
+			p = committed(p, len(res) == cutIndex)
+		}
+		res = append(res, p)
+	}
+	if cutIndex >= 0 && cutIndex == len(res) {
+		panic("Cut must be followed by a parser")
+	}
+	return res
+}
+
+// committed returns with a parser which returns a fatal error if the parser doesn't match
+// If first is true then the history is committed before calling the parser.
+func committed(p parsley.Parser, first bool) *parser.NamedFunc {
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		if first {
+			parsley.Commit(h, pos)
+		}
+		res, err, cp := parsley.Call(h, p, leftRecCtx, r, pos)
+		// a curtailed left recursion is not a real failure
+		if res != nil || cp.Len() > 0 || err != nil && parsley.IsFatal(err) {
+			return res, err, cp
+		}
+		if err == nil {
+			err = parsley.NewExpectedError(pos, p.Name())
+		}
+		err = parsley.DescribeError(r, err)
+		return nil, parsley.NewFatalError(err.Pos(), err), cp
+	}).WithName(p.Name).WithChildren(parsley.KindWrapper, p)
+}
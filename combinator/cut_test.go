@@ -0,0 +1,106 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package combinator_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+// newStatementParser returns with a parser for "function <name>" or "<name> = <integer>" statements
+func newStatementParser(keyword parsley.Parser) parsley.Parser {
+	ident := text.LeftTrim(terminal.Regexp("IDENT", "identifier", "[a-z]+", 0), text.WsSpaces)
+	return combinator.Sentence(combinator.Choice("statement",
+		combinator.Seq("FUNCTION", "function", keyword, combinator.Cut(), ident),
+		combinator.Seq("ASSIGN", "assignment",
+			ident,
+			text.LeftTrim(terminal.Rune('='), text.WsSpaces),
+			text.LeftTrim(terminal.Integer(), text.WsSpaces),
+		),
+	))
+}
+
+// Let's commit a function definition after the "function" keyword, so the error will point to the missing function
+// name instead of the "=" expected by the assignment.
+func ExampleCut() {
+	p := newStatementParser(terminal.Word("function", "function"))
+	f := text.NewFile("example.file", []byte("function 123"))
+	_, err := parsley.Parse(parser.NewHistory(), text.NewReader(f), p)
+	fmt.Println(parsley.NewFileSet(f).ErrorWithPosition(err))
+	// Output: was expecting identifier, found "1" at example.file:1:10
+}
+
+var _ = Describe("Cut", func() {
+	var keyword parsley.Parser
+
+	BeforeEach(func() {
+		keyword = terminal.Word("function", "function")
+	})
+
+	It("should not add a node to the sequence", func() {
+		f := text.NewFile("", []byte("function foo"))
+		node, err := parsley.Parse(parser.NewHistory(), text.NewReader(f), newStatementParser(keyword))
+		Expect(err).ToNot(HaveOccurred())
+		seq := node.(*ast.NonTerminalNode).Children()[0].(*ast.NonTerminalNode)
+		Expect(seq.Token()).To(Equal("FUNCTION"))
+		Expect(seq.Children()).To(HaveLen(2))
+	})
+
+	It("should try the other alternatives before the cut", func() {
+		f := text.NewFile("", []byte("foo = 1"))
+		_, err := parsley.Parse(parser.NewHistory(), text.NewReader(f), newStatementParser(keyword))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should return a fatal error at the position of the failure after the cut", func() {
+		f := text.NewFile("", []byte("function = 1"))
+		_, err := parsley.Parse(parser.NewHistory(), text.NewReader(f), newStatementParser(keyword))
+		Expect(parsley.IsFatal(err)).To(BeTrue())
+		Expect(err.Pos()).To(Equal(f.Pos(9)))
+		Expect(err).To(MatchError(`was expecting identifier, found "="`))
+	})
+
+	It("should commit the history", func() {
+		store := parser.NewWindowMemoStore(0)
+		h := parser.NewHistory().WithMemoStore(store)
+		f := text.NewFile("", []byte("function foo"))
+		_, err := parsley.Parse(h, text.NewReader(f), newStatementParser(combinator.Memoize(keyword)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Len()).To(Equal(0))
+	})
+
+	It("should match an empty input when used outside of a sequence", func() {
+		r := text.NewReader(text.NewFile("", []byte("foo")))
+		node, err, cp := combinator.Cut().Parse(parser.NewHistory(), data.EmptyIntMap, r, 1)
+		Expect(node).To(Equal(ast.NilNode(1)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cp).To(Equal(data.EmptyIntSet))
+	})
+
+	It("should panic if it's the last parser of a sequence", func() {
+		Expect(func() { combinator.Seq("FUNCTION", "function", keyword, combinator.Cut()) }).To(Panic())
+		Expect(func() { combinator.SeqTry("FUNCTION", "function", keyword, combinator.Cut(), combinator.Cut()) }).To(Panic())
+	})
+
+	It("should not be a child of the sequence", func() {
+		p := combinator.Seq("FUNCTION", "function", keyword, combinator.Cut(), terminal.Integer())
+		children := parsley.ChildrenOf(p)
+		Expect(children).To(HaveLen(2))
+		Expect(children[0]).To(Equal(keyword))
+		Expect(parsley.KindOf(children[1])).To(Equal(parsley.KindWrapper))
+		Expect(children[1].Name()).To(Equal("integer value"))
+	})
+})
@@ -0,0 +1,157 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package combinator
+
+import (
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Dependent is a sequence combinator where the second parser depends on the value of the first match
+type Dependent struct {
+	token       string
+	name        string
+	p           parsley.Parser
+	next        func(value interface{}) parsley.Parser
+	interpreter parsley.Interpreter
+	valueCtx    interface{}
+}
+
+// NewDependent creates a combinator which applies p, evaluates the match and calls next with the value to get the
+// parser for the rest of the input (e.g. a length-prefixed string or a tag-length-value record).
+// The result is a non-terminal node with two children: the first match and the match of the returned parser.
+// If next returns nil then the first value is not accepted and the combinator doesn't match.
+//
+// The next function should only depend on the value, as the results might be memoized by the position. If the
+// returned parser should be memoized then it has to be created once (e.g. in a lookup table) and not on every call.
+func NewDependent(token string, name string, p parsley.Parser, next func(value interface{}) parsley.Parser) *Dependent {
+	if p == nil {
+		panic("NewDependent() should be called with a parser")
+	}
+	if next == nil {
+		panic("NewDependent() should be called with a next function")
+	}
+	return &Dependent{
+		token: token,
+		name:  name,
+		p:     p,
+		next:  next,
+	}
+}
+
+// Bind binds the given interpreter
+func (d *Dependent) Bind(interpreter parsley.Interpreter) *Dependent {
+	d.interpreter = interpreter
+	return d
+}
+
+// WithValueContext sets the context for evaluating the first match
+// The first match is evaluated during parsing, so the evaluation context of Evaluate is not available. If the
+// interpreters of the first parser need a context then it has to be set here.
+func (d *Dependent) WithValueContext(ctx interface{}) *Dependent {
+	d.valueCtx = ctx
+	return d
+}
+
+// Name returns with the parser's descriptive name
+func (d *Dependent) Name() string {
+	if d.name != "" {
+		return d.name
+	}
+	return d.p.Name()
+}
+
+// Kind returns with parsley.KindDependent
+func (d *Dependent) Kind() parsley.ParserKind {
+	return parsley.KindDependent
+}
+
+// Children returns with the first parser, the next parser is only known during parsing
+func (d *Dependent) Children() []parsley.Parser {
+	return []parsley.Parser{d.p}
+}
+
+// Parse parses the given input
+func (d *Dependent) Parse(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+	if err := h.Enter(pos); err != nil {
+		return nil, err, data.EmptyIntSet
+	}
+	defer h.Leave()
+
+	if err := h.RegisterCall(pos); err != nil {
+		return nil, err, data.EmptyIntSet
+	}
+	res, err, cp := parsley.Call(h, d.p, leftRecCtx, r, pos)
+	if err != nil && parsley.IsFatal(err) {
+		return nil, err, cp
+	}
+	if res == nil {
+		if err == nil {
+			err = expect(err, pos, d.p)
+		}
+		return nil, err, cp
+	}
+
+	var result parsley.Node
+	var nodes []parsley.Node
+	if nodeList, ok := res.(ast.NodeList); ok {
+		nodes = nodeList
+	} else {
+		nodes = []parsley.Node{res}
+	}
+
+	for _, node := range nodes {
+		value, valueErr := node.Value(d.valueCtx)
+		if valueErr != nil {
+			if valueErr.Pos() == parsley.NilPos {
+				valueErr = parsley.MoveError(valueErr, node.Pos())
+			}
+			err = parsley.MergeErrors(err, valueErr)
+			continue
+		}
+		nextParser := d.next(value)
+		if nextParser == nil {
+			continue
+		}
+
+		// the left recursion context and the curtailing parsers are only relevant if no input was consumed
+		nextLeftRecCtx := data.EmptyIntMap
+		if node.ReaderPos() == pos {
+			nextLeftRecCtx = leftRecCtx
+		}
+
+		if regErr := h.RegisterCall(node.ReaderPos()); regErr != nil {
+			return nil, regErr, cp
+		}
+		nextRes, nextErr, nextCP := parsley.Call(h, nextParser, nextLeftRecCtx, r, node.ReaderPos())
+		if node.ReaderPos() == pos {
+			cp = cp.Union(nextCP)
+		}
+		if nextErr != nil && parsley.IsFatal(nextErr) {
+			return nil, nextErr, cp
+		}
+		if nextErr != nil {
+			err = parsley.MergeErrors(err, nextErr)
+		} else if nextRes == nil {
+			err = expect(err, node.ReaderPos(), nextParser)
+		}
+		if nextRes == nil {
+			continue
+		}
+
+		switch nextNodes := nextRes.(type) {
+		case ast.NodeList:
+			for _, nextNode := range nextNodes {
+				result = ast.AppendNode(result, ast.NewNonTerminalNode(d.token, []parsley.Node{node, nextNode}, d.interpreter))
+			}
+		default:
+			result = ast.AppendNode(result, ast.NewNonTerminalNode(d.token, []parsley.Node{node, nextNodes}, d.interpreter))
+		}
+	}
+
+	return result, err, cp
+}
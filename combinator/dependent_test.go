@@ -0,0 +1,339 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package combinator_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/ast/interpreter"
+	"github.com/sniperkit/snk.fork.parsley/binary"
+	bterminal "github.com/sniperkit/snk.fork.parsley/binary/terminal"
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/parsley/parsleyfakes"
+)
+
+// Let's define a parser for length-prefixed strings where the first byte is the length of the string.
+func ExampleNewDependent() {
+	p := combinator.NewDependent("STR", "string", bterminal.Uint8(), func(value interface{}) parsley.Parser {
+		return bterminal.Raw(int(value.(uint8)))
+	}).Bind(ast.InterpreterFunc(func(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+		value, err := nodes[1].Value(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return string(value.([]byte)), nil
+	}))
+	r := binary.NewReader(binary.NewFile("example.bin", []byte{0x05, 'h', 'e', 'l', 'l', 'o'}))
+	value, _ := parsley.Evaluate(parser.NewHistory(), r, combinator.Sentence(p), nil)
+	fmt.Printf("%T %v\n", value, value)
+	// Output: string hello
+}
+
+// Let's define a parser for a counted array where the first byte is the number of the 16-bit elements.
+func ExampleCount() {
+	p := combinator.NewDependent("ARRAY", "array", bterminal.Uint8(), func(value interface{}) parsley.Parser {
+		return combinator.Count("ITEMS", "items", int(value.(uint8)), bterminal.Uint16(binary.BigEndian)).Bind(interpreter.Array())
+	}).Bind(interpreter.Select(1))
+	r := binary.NewReader(binary.NewFile("example.bin", []byte{0x01, 0x00, 0x2a}))
+	value, _ := parsley.Evaluate(parser.NewHistory(), r, combinator.Sentence(p), nil)
+	fmt.Printf("%T %v\n", value, value)
+	// Output: []interface {} [42]
+}
+
+var _ = Describe("NewDependent", func() {
+
+	var (
+		p                    *combinator.Dependent
+		h                    *parsleyfakes.FakeHistory
+		r                    *parsleyfakes.FakeReader
+		p1, p2               *parsleyfakes.FakeParser
+		leftRecCtx           data.IntMap
+		pos                  parsley.Pos
+		cp, p1CP, p2CP       data.IntSet
+		res, p1Res, p2Res    parsley.Node
+		parserErr, p1Err     parsley.Error
+		p2Err                parsley.Error
+		n1, n2               *parsleyfakes.FakeNode
+		nextValues           []interface{}
+		nextReturnsNilParser bool
+	)
+
+	BeforeEach(func() {
+		h = &parsleyfakes.FakeHistory{}
+		r = &parsleyfakes.FakeReader{}
+		p1 = &parsleyfakes.FakeParser{}
+		p1.NameReturns("p1")
+		p2 = &parsleyfakes.FakeParser{}
+		p2.NameReturns("p2")
+		leftRecCtx = data.NewIntMap(map[int]int{1: 2})
+		pos = parsley.Pos(1)
+
+		n1 = &parsleyfakes.FakeNode{}
+		n1.TokenReturns("n1")
+		n1.ValueReturns(3, nil)
+		n1.ReaderPosReturns(parsley.Pos(2))
+		n2 = &parsleyfakes.FakeNode{}
+		n2.TokenReturns("n2")
+		n2.ReaderPosReturns(parsley.Pos(5))
+
+		p1CP = data.NewIntSet(1)
+		p2CP = data.NewIntSet(2)
+		p1Res = n1
+		p2Res = n2
+		p1Err = nil
+		p2Err = nil
+		nextValues = nil
+		nextReturnsNilParser = false
+	})
+
+	JustBeforeEach(func() {
+		p1.ParseReturns(p1Res, p1Err, p1CP)
+		p2.ParseReturns(p2Res, p2Err, p2CP)
+
+		p = combinator.NewDependent("TEST", "test", p1, func(value interface{}) parsley.Parser {
+			nextValues = append(nextValues, value)
+			if nextReturnsNilParser {
+				return nil
+			}
+			return p2
+		})
+		res, parserErr, cp = p.Parse(h, leftRecCtx, r, pos)
+	})
+
+	It("should have a name", func() {
+		Expect(p.Name()).To(Equal("test"))
+	})
+
+	It("should panic if called without a parser or next function", func() {
+		Expect(func() { combinator.NewDependent("TEST", "", nil, func(interface{}) parsley.Parser { return nil }) }).To(Panic())
+		Expect(func() { combinator.NewDependent("TEST", "", p1, nil) }).To(Panic())
+	})
+
+	It("should call the next parser with the value of the first match", func() {
+		Expect(nextValues).To(Equal([]interface{}{3}))
+
+		_, passedLeftRecCtx, passedR, passedPos := p1.ParseArgsForCall(0)
+		Expect(passedLeftRecCtx).To(Equal(leftRecCtx))
+		Expect(passedR).To(BeEquivalentTo(r))
+		Expect(passedPos).To(Equal(pos))
+
+		_, passedLeftRecCtx, passedR, passedPos = p2.ParseArgsForCall(0)
+		Expect(passedLeftRecCtx).To(Equal(data.EmptyIntMap))
+		Expect(passedR).To(BeEquivalentTo(r))
+		Expect(passedPos).To(Equal(parsley.Pos(2)))
+	})
+
+	It("should return with a node containing both matches", func() {
+		Expect(parserErr).ToNot(HaveOccurred())
+		Expect(res).To(Equal(ast.NewNonTerminalNode("TEST", []parsley.Node{n1, n2}, nil)))
+	})
+
+	It("should only return the curtailing parsers of the first parser if it consumed input", func() {
+		Expect(cp).To(Equal(p1CP))
+	})
+
+	It("should register the calls and track the depth", func() {
+		Expect(h.RegisterCallCallCount()).To(Equal(2))
+		Expect(h.RegisterCallArgsForCall(1)).To(Equal(parsley.Pos(2)))
+		Expect(h.EnterCallCount()).To(Equal(1))
+		Expect(h.LeaveCallCount()).To(Equal(1))
+	})
+
+	Context("when the first parser doesn't consume any input", func() {
+		BeforeEach(func() {
+			n1.ReaderPosReturns(pos)
+		})
+
+		It("should pass the left recursion context and merge the curtailing parsers", func() {
+			_, passedLeftRecCtx, _, _ := p2.ParseArgsForCall(0)
+			Expect(passedLeftRecCtx).To(Equal(leftRecCtx))
+			Expect(cp).To(Equal(p1CP.Union(p2CP)))
+		})
+	})
+
+	Context("when the first parser doesn't match", func() {
+		BeforeEach(func() {
+			p1Res = nil
+		})
+
+		It("should return an expected error", func() {
+			Expect(res).To(BeNil())
+			Expect(parserErr).To(Equal(parsley.NewExpectedError(pos, "p1")))
+			Expect(p2.ParseCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the first parser returns with a fatal error", func() {
+		BeforeEach(func() {
+			p1Res = nil
+			p1Err = parsley.NewFatalError(pos, errors.New("fatal"))
+		})
+
+		It("should return the error", func() {
+			Expect(res).To(BeNil())
+			Expect(parserErr).To(BeIdenticalTo(p1Err))
+		})
+	})
+
+	Context("when the value of the first match can not be evaluated", func() {
+		var valueErr parsley.Error
+
+		BeforeEach(func() {
+			valueErr = parsley.NewErrorf(pos, "invalid value")
+			n1.ValueReturns(nil, valueErr)
+		})
+
+		It("should return the error", func() {
+			Expect(res).To(BeNil())
+			Expect(parserErr).To(BeIdenticalTo(valueErr))
+			Expect(p2.ParseCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the value error has no position", func() {
+		var valueErr parsley.Error
+
+		BeforeEach(func() {
+			valueErr = parsley.NewErrorf(parsley.NilPos, "invalid value")
+			n1.ValueReturns(nil, valueErr)
+			n1.PosReturns(pos)
+		})
+
+		It("should return the error at the position of the first match", func() {
+			Expect(res).To(BeNil())
+			Expect(parserErr).To(MatchError("invalid value"))
+			Expect(parserErr.Pos()).To(Equal(pos))
+		})
+	})
+
+	Context("when the next function returns nil", func() {
+		BeforeEach(func() {
+			nextReturnsNilParser = true
+		})
+
+		It("should not match", func() {
+			Expect(res).To(BeNil())
+			Expect(parserErr).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("when the next parser doesn't match", func() {
+		BeforeEach(func() {
+			p2Res = nil
+		})
+
+		It("should return an expected error at the end of the first match", func() {
+			Expect(res).To(BeNil())
+			Expect(parserErr).To(Equal(parsley.NewExpectedError(parsley.Pos(2), "p2")))
+		})
+	})
+
+	Context("when the next parser returns with a fatal error", func() {
+		BeforeEach(func() {
+			p2Res = nil
+			p2Err = parsley.NewFatalError(parsley.Pos(2), errors.New("fatal"))
+		})
+
+		It("should return the error", func() {
+			Expect(res).To(BeNil())
+			Expect(parserErr).To(BeIdenticalTo(p2Err))
+		})
+	})
+
+	Context("when the first parser has multiple matches", func() {
+		var n3 *parsleyfakes.FakeNode
+
+		BeforeEach(func() {
+			n3 = &parsleyfakes.FakeNode{}
+			n3.ValueReturns(4, nil)
+			n3.ReaderPosReturns(parsley.Pos(3))
+			p1Res = ast.NodeList([]parsley.Node{n1, n3})
+		})
+
+		It("should try the next parser for all of them", func() {
+			Expect(nextValues).To(Equal([]interface{}{3, 4}))
+			Expect(res).To(Equal(ast.NodeList([]parsley.Node{
+				ast.NewNonTerminalNode("TEST", []parsley.Node{n1, n2}, nil),
+				ast.NewNonTerminalNode("TEST", []parsley.Node{n3, n2}, nil),
+			})))
+		})
+	})
+
+	Context("when the history returns with an error", func() {
+		var historyErr parsley.Error
+
+		BeforeEach(func() {
+			historyErr = parsley.NewFatalError(pos, errors.New("aborted"))
+			h.RegisterCallReturnsOnCall(1, historyErr)
+		})
+
+		It("should not call the next parser and return with the error", func() {
+			Expect(res).To(BeNil())
+			Expect(parserErr).To(BeIdenticalTo(historyErr))
+			Expect(p2.ParseCallCount()).To(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("NewDependent with an interpreted first match", func() {
+	// the first match is the sum of two bytes multiplied by the factor in the context
+	newParser := func() *combinator.Dependent {
+		sum := ast.InterpreterFunc(func(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+			v1, _ := nodes[0].Value(ctx)
+			v2, _ := nodes[1].Value(ctx)
+			return (int(v1.(uint8)) + int(v2.(uint8))) * *ctx.(*int), nil
+		})
+		first := combinator.Seq("SUM", "sum", bterminal.Uint8(), bterminal.Uint8()).Bind(sum)
+		return combinator.NewDependent("ARRAY", "array", first, func(value interface{}) parsley.Parser {
+			return combinator.Count("ITEMS", "items", value.(int), bterminal.Uint8())
+		})
+	}
+
+	It("should evaluate the first match with the value context", func() {
+		factor := 2
+		p := newParser().WithValueContext(&factor)
+		f := binary.NewFile("testfile", []byte{0x01, 0x01, 0x0a, 0x0b, 0x0c, 0x0d})
+		res, err, _ := p.Parse(parser.NewHistory(), data.EmptyIntMap, binary.NewReader(f), f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.ReaderPos()).To(Equal(f.Pos(6)))
+	})
+})
+
+var _ = Describe("Count", func() {
+	It("should panic if called with a negative count", func() {
+		Expect(func() { combinator.Count("TEST", "", -1, &parsleyfakes.FakeParser{}) }).To(Panic())
+	})
+
+	It("should return an empty match if the count is zero", func() {
+		f := binary.NewFile("testfile", []byte{0x01})
+		res, err, _ := combinator.Count("TEST", "test", 0, bterminal.Uint8()).Parse(parser.NewHistory(), data.EmptyIntMap, binary.NewReader(f), f.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(ast.NewEmptyNonTerminalNode("TEST", f.Pos(0), nil)))
+	})
+
+	It("should not match if there are less items", func() {
+		f := binary.NewFile("testfile", []byte{0x01})
+		res, err, _ := combinator.Count("TEST", "test", 2, bterminal.Uint8()).Parse(parser.NewHistory(), data.EmptyIntMap, binary.NewReader(f), f.Pos(0))
+		Expect(res).To(BeNil())
+		Expect(err).To(MatchError("was expecting uint8"))
+	})
+
+	It("should expose the count", func() {
+		n, ok := combinator.Count("TEST", "test", 3, bterminal.Uint8()).Count()
+		Expect(n).To(Equal(3))
+		Expect(ok).To(BeTrue())
+		_, ok = combinator.Many(bterminal.Uint8()).Count()
+		Expect(ok).To(BeFalse())
+	})
+})
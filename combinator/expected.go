@@ -0,0 +1,29 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// expect adds the parser's name to the expected parsers if the parser didn't match at the given position
+// It's a no-op if the existing error has a higher position or the parser has no name.
+func expect(err parsley.Error, pos parsley.Pos, p parsley.Parser) parsley.Error {
+	if err != nil && err.Pos() > pos {
+		return err
+	}
+	name := p.Name()
+	if name == "" {
+		return err
+	}
+	return parsley.MergeErrors(err, parsley.NewExpectedError(pos, name))
+}
@@ -30,6 +30,10 @@ func Many1(p parsley.Parser) *Recursive {
 }
 
 func newMany(p parsley.Parser, allowEmpty bool) *Recursive {
+	kind := parsley.KindMany1
+	if allowEmpty {
+		kind = parsley.KindMany
+	}
 	name := func() string {
 		return fmt.Sprintf("one or more %s", inflection.Plural(p.Name()))
 	}
@@ -39,5 +43,5 @@ func newMany(p parsley.Parser, allowEmpty bool) *Recursive {
 	lenCheck := func(len int) bool {
 		return allowEmpty || len >= 0
 	}
-	return NewRecursive("MANY", name, lookup, lenCheck)
+	return NewRecursive("MANY", name, lookup, lenCheck).WithChildren(kind, p)
 }
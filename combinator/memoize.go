@@ -33,7 +33,10 @@ func Memoize(p parsley.Parser) *parser.NamedFunc {
 			return nil, nil, data.NewIntSet(parserIndex)
 		}
 
-		node, err, cp := p.Parse(h, leftRecCtx.Inc(parserIndex), r, pos)
+		node, err, cp := parsley.Call(h, p, leftRecCtx.Inc(parserIndex), r, pos)
+		if err != nil && parsley.IsFatal(err) {
+			return nil, err, cp
+		}
 		leftRecCtx = leftRecCtx.Filter(cp)
 
 		res := &parsley.Result{
@@ -42,8 +45,10 @@ func Memoize(p parsley.Parser) *parser.NamedFunc {
 			Node:              node,
 			Err:               err,
 		}
-		h.SaveResult(parserIndex, pos, res)
+		if err := h.SaveResult(parserIndex, pos, res); err != nil {
+			return nil, err, cp
+		}
 
 		return node, err, cp
-	}).WithName(p.Name)
+	}).WithName(p.Name).WithChildren(parsley.KindMemoize, p)
 }
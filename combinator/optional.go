@@ -19,9 +19,13 @@ import (
 )
 
 // Optional returns the parser's matches and an empty match
-func Optional(p parsley.Parser) parser.Func {
+// The parser has the same name as the given parser.
+func Optional(p parsley.Parser) *parser.NamedFunc {
 	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
-		res, err, cp := p.Parse(h, leftRecCtx, r, pos)
+		res, err, cp := parsley.Call(h, p, leftRecCtx, r, pos)
+		if err != nil && parsley.IsFatal(err) {
+			return nil, err, cp
+		}
 		return ast.AppendNode(res, ast.NilNode(pos)), err, cp
-	})
+	}).WithName(p.Name).WithChildren(parsley.KindOptional, p)
 }
@@ -0,0 +1,75 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator
+
+import (
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Recover returns the parser's matches or recovers from a syntax error
+// If the parser doesn't match then the input is skipped until the sync parser matches or the end of the input is
+// reached. The skipped input is returned as an error node containing the syntax error, so the parsing can continue.
+// The sync parser's match is not consumed. If no input was skipped (the sync parser matches at the current position or
+// the end of the input was reached) then the parser's error is returned, so the repetitions of Recover will stop.
+// The input is skipped by characters or tokens if the reader implements parsley.Advancer, otherwise by bytes.
+// The syntax errors can be collected from the result with ast.Errors.
+func Recover(p parsley.Parser, sync parsley.Parser) *parser.NamedFunc {
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		res, err, cp := parsley.Call(h, p, leftRecCtx, r, pos)
+		if res != nil || (err != nil && parsley.IsFatal(err)) {
+			return res, err, cp
+		}
+		if err == nil {
+			err = expect(err, pos, p)
+		}
+		if err == nil {
+			err = parsley.NewExpectedError(pos)
+		}
+		err = parsley.DescribeError(r, err)
+
+		readerPos := pos
+		syncLeftRecCtx := leftRecCtx
+		for !r.IsEOF(readerPos) {
+			if err := h.RegisterCall(readerPos); err != nil {
+				return nil, err, cp
+			}
+			if readerPos > pos {
+				syncLeftRecCtx = data.EmptyIntMap
+			}
+			syncRes, syncErr, _ := parsley.Call(h, sync, syncLeftRecCtx, r, readerPos)
+			if syncErr != nil && parsley.IsFatal(syncErr) {
+				return nil, syncErr, cp
+			}
+			if syncRes != nil {
+				break
+			}
+			readerPos = advance(r, readerPos)
+		}
+
+		if readerPos == pos {
+			return nil, err, cp
+		}
+
+		return ast.NewErrorNode(err, pos, readerPos), nil, cp
+	}).WithName(p.Name).WithChildren(parsley.KindRecover, p, sync)
+}
+
+// advance returns with the position of the next input unit
+func advance(r parsley.Reader, pos parsley.Pos) parsley.Pos {
+	if a, ok := r.(parsley.Advancer); ok {
+		return a.Advance(pos)
+	}
+	return pos + 1
+}
@@ -0,0 +1,276 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package combinator_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/parsley/parsleyfakes"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+// Let's define a parser which accepts a list of integers separated by semicolons.
+// If an item is invalid we skip to the next semicolon and report all the errors at the end.
+func ExampleRecover() {
+	p := combinator.SepBy(
+		combinator.Recover(terminal.Integer(), terminal.Rune(';')),
+		terminal.Rune(';'),
+	)
+	r := text.NewReader(text.NewFile("example.file", []byte("1;a;2;b")))
+	node, _ := parsley.Parse(parser.NewHistory(), r, combinator.Sentence(p))
+	for _, err := range ast.Errors(node) {
+		fmt.Printf("%d: %s\n", err.Pos(), err)
+	}
+	// Output:
+	// 3: was expecting integer value, found "a"
+	// 7: was expecting integer value, found "b"
+}
+
+var _ = Describe("Recover", func() {
+
+	var (
+		h               parsley.History
+		r               *text.Reader
+		p, sync         *parsleyfakes.FakeParser
+		leftRecCtx      data.IntMap
+		pos             parsley.Pos
+		cp, pCP         data.IntSet
+		res, pRes       parsley.Node
+		parserErr, pErr parsley.Error
+		input           string
+	)
+
+	BeforeEach(func() {
+		h = parser.NewHistory()
+		p = &parsleyfakes.FakeParser{}
+		p.NameReturns("p")
+		sync = &parsleyfakes.FakeParser{}
+		sync.ParseStub = func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+			return terminal.Rune(';').Parse(h, leftRecCtx, r, pos)
+		}
+		leftRecCtx = data.EmptyIntMap
+		pCP = data.NewIntSet(1)
+		pRes = nil
+		pErr = nil
+		input = "abc;d"
+	})
+
+	JustBeforeEach(func() {
+		r = text.NewReader(text.NewFile("textfile", []byte(input)))
+		pos = r.Pos(0)
+		p.ParseReturns(pRes, pErr, pCP)
+		res, parserErr, cp = combinator.Recover(p, sync).Parse(h, leftRecCtx, r, pos)
+	})
+
+	It("should have the name of the parser", func() {
+		Expect(combinator.Recover(p, sync).Name()).To(Equal("p"))
+	})
+
+	Context("when the parser matches", func() {
+		BeforeEach(func() {
+			pRes = ast.NewTerminalNode("STR", "abc", parsley.Pos(1), parsley.Pos(4))
+			pErr = parsley.NewErrorf(parsley.Pos(4), "some error")
+		})
+
+		It("should return the result of the parser", func() {
+			Expect(res).To(Equal(pRes))
+			Expect(parserErr).To(Equal(pErr))
+			Expect(cp).To(Equal(pCP))
+		})
+
+		It("should not call the sync parser", func() {
+			Expect(sync.ParseCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the parser doesn't match", func() {
+		BeforeEach(func() {
+			pErr = parsley.NewErrorf(parsley.Pos(2), "some error")
+		})
+
+		It("should skip the input until the sync parser matches", func() {
+			Expect(res).To(Equal(ast.NewErrorNode(pErr, pos, parsley.Pos(4))))
+			Expect(parserErr).To(BeNil())
+			Expect(cp).To(Equal(pCP))
+		})
+
+		It("should call the sync parser on every position", func() {
+			Expect(sync.ParseCallCount()).To(Equal(4))
+			for i := 0; i < 4; i++ {
+				_, _, _, passedPos := sync.ParseArgsForCall(i)
+				Expect(passedPos).To(Equal(pos + parsley.Pos(i)))
+			}
+		})
+
+		Context("when there is a left recursion context", func() {
+			BeforeEach(func() {
+				leftRecCtx = data.EmptyIntMap.Inc(1)
+			})
+
+			It("should reset it when the input is skipped", func() {
+				_, passedLeftRecCtx, _, _ := sync.ParseArgsForCall(0)
+				Expect(passedLeftRecCtx).To(Equal(leftRecCtx))
+				for i := 1; i < 4; i++ {
+					_, passedLeftRecCtx, _, _ = sync.ParseArgsForCall(i)
+					Expect(passedLeftRecCtx).To(Equal(data.EmptyIntMap))
+				}
+			})
+		})
+
+		Context("when the sync parser matches at the current position", func() {
+			BeforeEach(func() {
+				input = ";abc"
+			})
+
+			It("should return with the parser's error", func() {
+				Expect(res).To(BeNil())
+				Expect(parserErr).To(Equal(pErr))
+				Expect(cp).To(Equal(pCP))
+			})
+		})
+
+		Context("when the input is at the end", func() {
+			BeforeEach(func() {
+				input = ""
+			})
+
+			It("should return with the parser's error", func() {
+				Expect(res).To(BeNil())
+				Expect(parserErr).To(Equal(pErr))
+				Expect(sync.ParseCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the input contains multi-byte characters", func() {
+			BeforeEach(func() {
+				input = "🍕;d"
+			})
+
+			It("should skip whole characters", func() {
+				Expect(res).To(Equal(ast.NewErrorNode(pErr, pos, parsley.Pos(5))))
+				Expect(sync.ParseCallCount()).To(Equal(2))
+				_, _, _, passedPos := sync.ParseArgsForCall(1)
+				Expect(passedPos).To(Equal(parsley.Pos(5)))
+			})
+		})
+
+		Context("when the sync parser never matches", func() {
+			BeforeEach(func() {
+				input = "abc"
+			})
+
+			It("should skip the input until the end", func() {
+				Expect(res).To(Equal(ast.NewErrorNode(pErr, pos, parsley.Pos(4))))
+			})
+		})
+
+		Context("when the parser has no error", func() {
+			BeforeEach(func() {
+				pErr = nil
+			})
+
+			It("should use the parser's name in the error", func() {
+				expectedErr := parsley.NewExpectedError(pos, "p")
+				expectedErr.Found = `"a"`
+				Expect(res).To(Equal(ast.NewErrorNode(expectedErr, pos, parsley.Pos(4))))
+			})
+		})
+
+		Context("when the sync parser returns with a fatal error", func() {
+			var syncErr parsley.Error
+
+			BeforeEach(func() {
+				syncErr = parsley.NewFatalError(parsley.Pos(1), fmt.Errorf("fatal"))
+				sync.ParseStub = nil
+				sync.ParseReturns(nil, syncErr, data.EmptyIntSet)
+			})
+
+			It("should return with the error", func() {
+				Expect(res).To(BeNil())
+				Expect(parserErr).To(BeIdenticalTo(syncErr))
+			})
+		})
+
+		Context("when the history returns with an error", func() {
+			var historyErr parsley.Error
+
+			BeforeEach(func() {
+				historyErr = parsley.NewFatalError(parsley.Pos(1), fmt.Errorf("aborted"))
+				fakeHistory := &parsleyfakes.FakeHistory{}
+				fakeHistory.RegisterCallReturns(historyErr)
+				h = fakeHistory
+			})
+
+			It("should return with the error", func() {
+				Expect(res).To(BeNil())
+				Expect(parserErr).To(BeIdenticalTo(historyErr))
+				Expect(sync.ParseCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when the parser returns with a fatal error", func() {
+		BeforeEach(func() {
+			pErr = parsley.NewFatalError(parsley.Pos(2), fmt.Errorf("fatal"))
+		})
+
+		It("should return with the error", func() {
+			Expect(res).To(BeNil())
+			Expect(parserErr).To(BeIdenticalTo(pErr))
+			Expect(sync.ParseCallCount()).To(Equal(0))
+		})
+	})
+
+	It("should not curtail a memoized left-recursive sync parser after skipping the input", func() {
+		var semicolons parser.NamedFunc
+		semicolons = *combinator.Memoize(combinator.Any("semicolons",
+			combinator.Seq("SEMICOLONS", "semicolons", &semicolons, terminal.Rune(';')),
+			terminal.Rune(';'),
+		))
+		r := text.NewReader(text.NewFile("", []byte("abc;;d")))
+
+		// find the memoized parser's index and simulate a deep left recursion at the starting position
+		fh := &parsleyfakes.FakeHistory{}
+		semicolons.Parse(fh, data.EmptyIntMap, r, r.Pos(0))
+		index, _, _ := fh.GetResultArgsForCall(0)
+		leftRecCtx := data.EmptyIntMap
+		for i := 0; i < 5; i++ {
+			leftRecCtx = leftRecCtx.Inc(index)
+		}
+
+		p := combinator.Recover(terminal.Integer(), &semicolons)
+		res, err, _ := p.Parse(parser.NewHistory(), leftRecCtx, r, r.Pos(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(res.ReaderPos()).To(Equal(r.Pos(3)))
+	})
+
+	It("should stop a repetition when no input can be skipped", func() {
+		stmt := text.LeftTrim(combinator.Seq("STMT", "statement", terminal.Rune('x'), terminal.Rune(';')), text.WsSpaces)
+		p := combinator.Many(combinator.Recover(stmt, terminal.Rune(';')))
+		h := parser.NewHistoryWithLimits(parser.Limits{MaxCalls: 1000})
+		r := text.NewReader(text.NewFile("", []byte("x; y; x;")))
+		node, err := parsley.Parse(h, r, p)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ast.Errors(node)).To(HaveLen(1))
+		Expect(node.ReaderPos()).To(Equal(r.Pos(4)))
+	})
+})
@@ -24,18 +24,39 @@ type Recursive struct {
 	parserLookUp func(int) parsley.Parser
 	lenCheck     func(int) bool
 	interpreter  parsley.Interpreter
+	kind         parsley.ParserKind
+	children     []parsley.Parser
+	count        int
+	counted      bool
 }
 
 // NewRecursive creates a new recursive instance
+// The structure of the parser is not known, its kind will be parsley.KindRecursive without children unless it's set
+// with WithChildren.
 func NewRecursive(token string, name func() string, parserLookUp func(int) parsley.Parser, lenCheck func(int) bool) *Recursive {
 	return &Recursive{
 		token:        token,
 		name:         name,
 		parserLookUp: parserLookUp,
 		lenCheck:     lenCheck,
+		kind:         parsley.KindRecursive,
 	}
 }
 
+// WithChildren sets the kind and the child parsers, so the parser's structure can be inspected
+func (rp *Recursive) WithChildren(kind parsley.ParserKind, children ...parsley.Parser) *Recursive {
+	rp.kind = kind
+	rp.children = children
+	return rp
+}
+
+// WithCount sets the fixed number of times the child parser is applied, see parsley.Counter
+func (rp *Recursive) WithCount(n int) *Recursive {
+	rp.count = n
+	rp.counted = true
+	return rp
+}
+
 // Bind binds the given interpreter
 func (rp *Recursive) Bind(interpreter parsley.Interpreter) *Recursive {
 	rp.interpreter = interpreter
@@ -44,6 +65,11 @@ func (rp *Recursive) Bind(interpreter parsley.Interpreter) *Recursive {
 
 // Parse parses the given input
 func (rp *Recursive) Parse(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+	if err := h.Enter(pos); err != nil {
+		return nil, err, data.EmptyIntSet
+	}
+	defer h.Leave()
+
 	p := &recursive{
 		token:             rp.token,
 		parserLookUp:      rp.parserLookUp,
@@ -60,6 +86,21 @@ func (rp *Recursive) Name() string {
 	return rp.name()
 }
 
+// Kind returns with the parser kind
+func (rp *Recursive) Kind() parsley.ParserKind {
+	return rp.kind
+}
+
+// Children returns with the child parsers
+func (rp *Recursive) Children() []parsley.Parser {
+	return rp.children
+}
+
+// Count returns with the fixed number of times the child parser is applied if it was set with WithCount
+func (rp *Recursive) Count() (int, bool) {
+	return rp.count, rp.counted
+}
+
 // recursive is a recursive and-type combinator
 type recursive struct {
 	token             string
@@ -84,10 +125,20 @@ func (rp *recursive) parse(depth int, h parsley.History, leftRecCtx data.IntMap,
 	var err parsley.Error
 	nextParser := rp.parserLookUp(depth)
 	if nextParser != nil {
-		h.RegisterCall()
-		res, err, cp = nextParser.Parse(h, leftRecCtx, r, pos)
-		if err != nil && (rp.err == nil || err.Pos() >= rp.err.Pos()) {
-			rp.err = err
+		if err = h.RegisterCall(pos); err != nil {
+			rp.result, rp.err = nil, err
+			return true
+		}
+		res, err, cp = parsley.Call(h, nextParser, leftRecCtx, r, pos)
+		if err != nil && parsley.IsFatal(err) {
+			rp.curtailingParsers = rp.curtailingParsers.Union(cp)
+			rp.result, rp.err = nil, err
+			return true
+		}
+		if err != nil {
+			rp.err = parsley.MergeErrors(rp.err, err)
+		} else if res == nil {
+			rp.err = expect(rp.err, pos, nextParser)
 		}
 	}
 
@@ -122,12 +173,6 @@ func (rp *recursive) parse(depth int, h parsley.History, leftRecCtx data.IntMap,
 			} else { // It's an empty result
 				rp.result = ast.AppendNode(rp.result, ast.NewEmptyNonTerminalNode(rp.token, pos, rp.interpreter))
 			}
-		} else {
-			if depth > 0 && nextParser != nil && nextParser.Name() != "" {
-				if err == nil && (rp.err == nil || pos > rp.err.Pos()) {
-					rp.err = parsley.NewErrorf(pos, "was expecting %s", nextParser.Name())
-				}
-			}
 		}
 	}
 	return false
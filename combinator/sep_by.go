@@ -43,5 +43,9 @@ func newSepBy(valueP parsley.Parser, sepP parsley.Parser, allowEmpty bool) *Recu
 	lenCheck := func(len int) bool {
 		return (len == 0 && allowEmpty) || len%2 == 1
 	}
-	return NewRecursive("SEP_BY", name, lookup, lenCheck)
+	kind := parsley.KindSepBy1
+	if allowEmpty {
+		kind = parsley.KindSepBy
+	}
+	return NewRecursive("SEP_BY", name, lookup, lenCheck).WithChildren(kind, valueP, sepP)
 }
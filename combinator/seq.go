@@ -18,18 +18,21 @@ import (
 // Seq tries to apply all parsers after each other matching effectively a sequence of tokens
 // and returns with all combinations of the results.
 // Only matches are returned where all parsers were applied successfully.
+// The sequence can be committed with Cut.
 func Seq(token string, name string, parsers ...parsley.Parser) *Recursive {
-	return newSeq(token, name, len(parsers), parsers...)
+	parsers = removeCuts(parsers)
+	return newSeq(token, name, len(parsers), parsley.KindSeq, parsers...)
 }
 
 // SeqTry tries to apply all parsers after each other matching effectively the longest possible sequences of
 // tokens and returns with all combinations of the results.
 // It needs to match the first parser at least
 func SeqTry(token string, name string, parsers ...parsley.Parser) *Recursive {
-	return newSeq(token, name, 1, parsers...)
+	parsers = removeCuts(parsers)
+	return newSeq(token, name, 1, parsley.KindSeqTry, parsers...)
 }
 
-func newSeq(token string, name string, min int, parsers ...parsley.Parser) *Recursive {
+func newSeq(token string, name string, min int, kind parsley.ParserKind, parsers ...parsley.Parser) *Recursive {
 	namef := parsers[0].Name
 	if name != "" {
 		namef = func() string { return name }
@@ -44,5 +47,5 @@ func newSeq(token string, name string, min int, parsers ...parsley.Parser) *Recu
 	lenCheck := func(len int) bool {
 		return len >= min && len <= l
 	}
-	return NewRecursive(token, namef, lookup, lenCheck)
+	return NewRecursive(token, namef, lookup, lenCheck).WithChildren(kind, parsers...)
 }
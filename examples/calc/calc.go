@@ -0,0 +1,41 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// This is a calculator example using a parser generated from a grammar definition (see calc/calc.ebnf).
+//
+// You can run this file to see the parser in action:
+//
+//	go run calc.go "1 + 2 * (3 - 1)"
+//
+// The output will be:
+//
+//	5
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sniperkit/snk.fork.parsley/examples/calc/calc"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+func main() {
+	input := "1 + 2 * (3 - 1)"
+	if len(os.Args) > 1 {
+		input = os.Args[1]
+	}
+	file := text.NewFile("input", []byte(input))
+	fs := parsley.NewFileSet(file)
+
+	res, evalErr := parsley.Evaluate(parser.NewHistory(), text.NewReader(file), calc.NewParser(), nil)
+	if evalErr != nil {
+		fmt.Fprintln(os.Stderr, fs.ErrorWithPosition(evalErr))
+		os.Exit(1)
+	}
+	fmt.Println(res)
+}
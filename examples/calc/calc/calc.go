@@ -0,0 +1,60 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Package calc is a calculator parser generated from the calc.ebnf grammar
+package calc
+
+//go:generate go run github.com/sniperkit/snk.fork.parsley/cmd/parsleygen -o calc_parser.go calc.ebnf
+
+import (
+	"errors"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// interpreters implements the generated Interpreters interface
+type interpreters struct{}
+
+func (interpreters) Add(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+	return binaryOp(ctx, nodes, func(a, b int) (int, error) { return a + b, nil })
+}
+
+func (interpreters) Sub(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+	return binaryOp(ctx, nodes, func(a, b int) (int, error) { return a - b, nil })
+}
+
+func (interpreters) Mul(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+	return binaryOp(ctx, nodes, func(a, b int) (int, error) { return a * b, nil })
+}
+
+func (interpreters) Div(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+	return binaryOp(ctx, nodes, func(a, b int) (int, error) {
+		if b == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return a / b, nil
+	})
+}
+
+func binaryOp(ctx interface{}, nodes []parsley.Node, op func(a, b int) (int, error)) (interface{}, parsley.Error) {
+	a, err := nodes[0].Value(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := nodes[2].Value(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, opErr := op(a.(int), b.(int))
+	if opErr != nil {
+		return nil, parsley.NewError(nodes[2].Pos(), opErr)
+	}
+	return res, nil
+}
+
+// NewParser returns with a parser which evaluates the whole input
+func NewParser() parsley.Parser {
+	return NewParsers(interpreters{}).Root()
+}
@@ -16,10 +16,16 @@ Sniperkit-Bot
 // By default the included example.json file will be used and the output will be:
 //  Parser calls: 240
 //  map[title:Person type:object properties:map[firstName:map[type:string] lastName:map[type:string] age:map[description:Age in years type:integer minimum:0]] required:[firstName lastName]]
+// The per-parser statistics can be printed with the -profile flag and a pprof profile can be written with
+// the -pprof flag:
+//  go run json.go -profile -pprof json.pprof example_10k.json
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 
 	"github.com/sniperkit/snk.fork.parsley/combinator"
@@ -30,9 +36,13 @@ import (
 )
 
 func main() {
+	profile := flag.Bool("profile", false, "print the parser statistics")
+	pprofFilePath := flag.String("pprof", "", "write a pprof profile to the given file")
+	flag.Parse()
+
 	jsonFilePath := "example.json"
-	if len(os.Args) > 1 {
-		jsonFilePath = os.Args[1]
+	if flag.NArg() > 0 {
+		jsonFilePath = flag.Arg(0)
 	}
 	fs := parsley.NewFileSet()
 	file, err := text.ReadFile(jsonFilePath)
@@ -41,7 +51,12 @@ func main() {
 	}
 	fs.AddFile(file)
 
-	h := parser.NewHistory()
+	var h parsley.History = parser.NewHistory()
+	var ph *parser.ProfileHistory
+	if *profile || *pprofFilePath != "" {
+		ph = parser.NewProfileHistory(h)
+		h = ph
+	}
 	reader := text.NewReader(file)
 	s := combinator.Sentence(json.NewParser())
 
@@ -51,4 +66,17 @@ func main() {
 	}
 	fmt.Printf("Parser calls: %d\n", h.CallCount())
 	fmt.Printf("%v\n", res)
+
+	if *profile {
+		ph.Stats().SortBy(parser.StatsSelfTime).WriteTable(os.Stdout)
+	}
+	if *pprofFilePath != "" {
+		var b bytes.Buffer
+		if err := ph.WritePprof(&b); err != nil {
+			panic(err)
+		}
+		if err := ioutil.WriteFile(*pprofFilePath, b.Bytes(), 0644); err != nil {
+			panic(err)
+		}
+	}
 }
@@ -0,0 +1,408 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package grammar
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sniperkit/snk.fork.parsley/ast/interpreter"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+var builtinParserCode = map[string]string{
+	"string":  "terminal.String(false)",
+	"integer": "terminal.Integer()",
+	"float":   "terminal.Float()",
+	"char":    "terminal.Char()",
+}
+
+var builtinInterpreterCode = map[string]string{
+	"array":  "interpreter.Array()",
+	"object": "interpreter.Object()",
+	"nil":    "interpreter.Nil()",
+}
+
+var wsModeCode = map[text.WsMode]string{
+	text.WsNone:     "text.WsNone",
+	text.WsSpaces:   "text.WsSpaces",
+	text.WsSpacesNl: "text.WsSpacesNl",
+}
+
+var goNameSeparatorRegexp = regexp.MustCompile(`_+`)
+
+// Generator generates Go source code from grammar definitions
+// The generated code builds the same parsers as the Loader, so the grammar is checked at compile time and there is
+// no startup cost of parsing the definition. Only the built-in parsers can be referenced in the rules.
+//
+// The generated file contains:
+//   - a Parsers struct with a field for every rule (the rule names are converted to CamelCase)
+//   - a NewParsers constructor and a Root method which matches the whole input using the first rule
+//   - an Interpreters interface with a method for every custom interpreter used in the grammar and an
+//     UnimplementedInterpreters struct which can be embedded to implement the interface partially
+type Generator struct {
+	pkg    string
+	wsMode text.WsMode
+}
+
+// NewGenerator creates a new generator for the given Go package name
+// The terminals will skip spaces, tabs and new lines by default.
+func NewGenerator(pkg string) *Generator {
+	return &Generator{
+		pkg:    pkg,
+		wsMode: text.WsSpacesNl,
+	}
+}
+
+// WithWsMode sets which whitespaces are skipped before the terminals
+func (g *Generator) WithWsMode(wsMode text.WsMode) *Generator {
+	g.wsMode = wsMode
+	return g
+}
+
+// Generate generates the Go source code for the grammar definition in the given file
+// The grammar is validated the same way as in Loader.Load and the errors have the same positions.
+func (g *Generator) Generate(f *text.File) ([]byte, parsley.Error) {
+	sp := &syntaxParser{r: text.NewReader(f), pos: f.Pos(0)}
+	defs, err := sp.parseGrammar()
+	if err != nil {
+		return nil, err
+	}
+
+	cg := &codeGenerator{
+		ws:           wsModeCode[g.wsMode],
+		rules:        make(map[string]string, len(defs)),
+		interpreters: map[string]string{},
+		imports:      map[string]bool{},
+	}
+
+	// the custom interpreters are only needed for the validation
+	l := NewLoader().WithWsMode(g.wsMode)
+	for _, def := range defs {
+		if err := cg.collectInterpreters(def.body, l); err != nil {
+			return nil, err
+		}
+	}
+
+	_, c, _, err := l.load(f)
+	if err != nil {
+		return nil, err
+	}
+	cg.compiler = c
+
+	goNames := map[string]string{"Root": "root"}
+	for _, def := range defs {
+		goName := toGoName(def.name)
+		if other, exists := goNames[goName]; exists {
+			return nil, parsley.NewErrorf(def.pos, "rule %q has the same Go name as %q: %s", def.name, other, goName)
+		}
+		goNames[goName] = def.name
+		cg.rules[def.name] = goName
+	}
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "\t// the rules are memoized so they can be left-recursive\n")
+	for _, def := range defs {
+		c.rule = def.name
+		code, err := cg.generateRule(def.body, def.name)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&body, "\t*p.%s = *combinator.Memoize(%s).WithName(%q)\n", cg.rules[def.name], code, def.name)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by parsleygen from %s. DO NOT EDIT.\n\n", filepath.Base(f.Filename()))
+	fmt.Fprintf(&out, "package %s\n\n", g.pkg)
+	cg.writeImports(&out)
+
+	fmt.Fprintf(&out, "// Parsers contains the parsers for the grammar rules\n")
+	fmt.Fprintf(&out, "type Parsers struct {\n")
+	for _, def := range defs {
+		fmt.Fprintf(&out, "\t%s *parser.NamedFunc\n", cg.rules[def.name])
+	}
+	fmt.Fprintf(&out, "}\n\n")
+
+	cg.writeInterpreters(&out)
+
+	fmt.Fprintf(&out, "// NewParsers creates the parsers for the grammar rules\n")
+	if len(cg.interpreters) > 0 {
+		fmt.Fprintf(&out, "func NewParsers(i Interpreters) *Parsers {\n")
+	} else {
+		fmt.Fprintf(&out, "func NewParsers() *Parsers {\n")
+	}
+	fmt.Fprintf(&out, "\tp := &Parsers{\n")
+	for _, def := range defs {
+		fmt.Fprintf(&out, "\t\t%s: parser.Func(nil).WithName(%q),\n", cg.rules[def.name], def.name)
+	}
+	fmt.Fprintf(&out, "\t}\n")
+	out.Write(body.Bytes())
+	fmt.Fprintf(&out, "\treturn p\n}\n\n")
+
+	fmt.Fprintf(&out, "// Root returns with a parser which matches the whole input using the first rule\n")
+	fmt.Fprintf(&out, "func (p *Parsers) Root() parsley.Parser {\n")
+	fmt.Fprintf(&out, "\treturn combinator.Sentence(text.RightTrim(p.%s, %s))\n}\n", cg.rules[defs[0].name], cg.ws)
+
+	res, formatErr := format.Source(out.Bytes())
+	if formatErr != nil {
+		return nil, invalidCodeError(out.Bytes(), formatErr)
+	}
+	return res, nil
+}
+
+// invalidCodeError returns with an error for the generated code which can not be formatted
+// The message contains the line of the unformatted code where the error happened, so it can be debugged.
+func invalidCodeError(code []byte, err error) parsley.Error {
+	if errs, ok := err.(scanner.ErrorList); ok && len(errs) > 0 {
+		lines := bytes.Split(code, []byte("\n"))
+		if line := errs[0].Pos.Line; line > 0 && line <= len(lines) {
+			return parsley.NewErrorf(parsley.NilPos, "the generated code is invalid: %s, line %d: %s", err, line, bytes.TrimSpace(lines[line-1]))
+		}
+	}
+	return parsley.NewErrorf(parsley.NilPos, "the generated code is invalid: %s", err)
+}
+
+// GenerateFile reads the grammar file and writes the generated code to the output file
+func (g *Generator) GenerateFile(filename string, output string) error {
+	f, err := text.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	code, genErr := g.Generate(f)
+	if genErr != nil {
+		if genErr.Pos() == parsley.NilPos {
+			return fmt.Errorf("%s: %s", filename, genErr.Error())
+		}
+		return fmt.Errorf("%s: %s", parsley.NewFileSet(f).Position(genErr.Pos()), genErr.Error())
+	}
+	return ioutil.WriteFile(output, code, 0644)
+}
+
+// codeGenerator generates the Go expressions for the grammar expressions
+type codeGenerator struct {
+	compiler     *compiler
+	ws           string
+	rules        map[string]string
+	interpreters map[string]string
+	imports      map[string]bool
+}
+
+// collectInterpreters collects the custom interpreters and registers a placeholder for them in the loader
+func (cg *codeGenerator) collectInterpreters(e expr, l *Loader) parsley.Error {
+	switch e := e.(type) {
+	case *seqExpr:
+		if _, builtin := l.interpreters[e.interpreter]; e.interpreter != "" && !builtin && !selectRegexp.MatchString(e.interpreter) {
+			if _, exists := cg.interpreters[e.interpreter]; !exists {
+				goName := toGoName(e.interpreter)
+				for name, other := range cg.interpreters {
+					if other == goName {
+						return parsley.NewErrorf(e.pos, "interpreter %q has the same Go name as %q: %s", e.interpreter, name, goName)
+					}
+				}
+				cg.interpreters[e.interpreter] = goName
+				l.RegisterInterpreter(e.interpreter, interpreter.Nil())
+			}
+		}
+		for _, item := range e.items {
+			if err := cg.collectInterpreters(item, l); err != nil {
+				return err
+			}
+		}
+	case *choiceExpr:
+		for _, alt := range e.alts {
+			if err := cg.collectInterpreters(alt, l); err != nil {
+				return err
+			}
+		}
+	case *optionalExpr:
+		return cg.collectInterpreters(e.expr, l)
+	case *manyExpr:
+		return cg.collectInterpreters(e.expr, l)
+	}
+	return nil
+}
+
+// generateRule generates the rule body, the top-level sequence or choice will have the rule's name
+func (cg *codeGenerator) generateRule(e expr, name string) (string, parsley.Error) {
+	switch e := e.(type) {
+	case *seqExpr:
+		return cg.generateSeq(e, name)
+	case *choiceExpr:
+		return cg.generateChoice(e, name)
+	default:
+		return cg.generate(e)
+	}
+}
+
+func (cg *codeGenerator) generate(e expr) (string, parsley.Error) {
+	switch e := e.(type) {
+	case *refExpr:
+		if goName, ok := cg.rules[e.name]; ok {
+			return "p." + goName, nil
+		}
+		cg.imports["text/terminal"] = true
+		return fmt.Sprintf("text.LeftTrim(%s, %s)", builtinParserCode[e.name], cg.ws), nil
+	case *literalExpr:
+		cg.imports["text/terminal"] = true
+		if wordRegexp.MatchString(e.value) {
+			return fmt.Sprintf("text.LeftTrim(terminal.Word(%q, %q), %s)", e.value, e.value, cg.ws), nil
+		}
+		return fmt.Sprintf("text.LeftTrim(terminal.Substring(%q, %q, %q), %s)", e.value, e.value, e.value, cg.ws), nil
+	case *regexpExpr:
+		cg.imports["text/terminal"] = true
+		return fmt.Sprintf("text.LeftTrim(terminal.Regexp(\"REGEXP\", %q, %q, 0), %s)", "`"+e.expr+"`", e.expr, cg.ws), nil
+	case *seqExpr:
+		return cg.generateSeq(e, "")
+	case *choiceExpr:
+		return cg.generateChoice(e, "")
+	case *optionalExpr:
+		code, err := cg.generate(e.expr)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("combinator.Optional(%s)", code), nil
+	case *manyExpr:
+		code, err := cg.generate(e.expr)
+		if err != nil {
+			return "", err
+		}
+		if e.min1 {
+			return fmt.Sprintf("combinator.Many1(%s)", code), nil
+		}
+		return fmt.Sprintf("combinator.Many(%s)", code), nil
+	default:
+		panic(fmt.Sprintf("unknown expression type: %T", e))
+	}
+}
+
+func (cg *codeGenerator) generateSeq(e *seqExpr, name string) (string, parsley.Error) {
+	items := make([]string, len(e.items))
+	for i, item := range e.items {
+		code, err := cg.generate(item)
+		if err != nil {
+			return "", err
+		}
+		items[i] = code
+	}
+	code := fmt.Sprintf("combinator.Seq(%q, %q, %s)", cg.compiler.rule, name, argList(items))
+
+	switch {
+	case e.interpreter == "":
+		return code, nil
+	case selectRegexp.MatchString(e.interpreter):
+		cg.imports["ast/interpreter"] = true
+		index := selectRegexp.FindStringSubmatch(e.interpreter)[1]
+		return fmt.Sprintf("%s.Bind(interpreter.Select(%s))", code, index), nil
+	case builtinInterpreterCode[e.interpreter] != "":
+		cg.imports["ast/interpreter"] = true
+		return fmt.Sprintf("%s.Bind(%s)", code, builtinInterpreterCode[e.interpreter]), nil
+	default:
+		cg.imports["ast"] = true
+		return fmt.Sprintf("%s.Bind(ast.InterpreterFunc(i.%s))", code, cg.interpreters[e.interpreter]), nil
+	}
+}
+
+func (cg *codeGenerator) generateChoice(e *choiceExpr, name string) (string, parsley.Error) {
+	alts := make([]string, len(e.alts))
+	names := make([]string, len(e.alts))
+	for i, alt := range e.alts {
+		code, err := cg.generate(alt)
+		if err != nil {
+			return "", err
+		}
+		alts[i] = code
+		if names[i], err = cg.name(alt); err != nil {
+			return "", err
+		}
+	}
+	if name == "" {
+		name = strings.Join(names, " or ")
+	}
+	if e.ordered {
+		return fmt.Sprintf("combinator.Choice(%q, %s)", name, argList(alts)), nil
+	}
+	return fmt.Sprintf("combinator.Any(%q, %s)", name, argList(alts)), nil
+}
+
+// name returns with the name of the parser compiled from the expression, so the names are the same as in the Loader
+func (cg *codeGenerator) name(e expr) (string, parsley.Error) {
+	p, err := cg.compiler.compile(e)
+	if err != nil {
+		return "", err
+	}
+	return p.Name(), nil
+}
+
+func (cg *codeGenerator) writeImports(out *bytes.Buffer) {
+	fmt.Fprintf(out, "import (\n")
+	for _, pkg := range []string{"ast", "ast/interpreter", "combinator", "parser", "parsley", "text", "text/terminal"} {
+		switch pkg {
+		case "combinator", "parser", "parsley", "text":
+		default:
+			if !cg.imports[pkg] {
+				continue
+			}
+		}
+		fmt.Fprintf(out, "\t\"github.com/sniperkit/snk.fork.parsley/%s\"\n", pkg)
+	}
+	fmt.Fprintf(out, ")\n\n")
+}
+
+func (cg *codeGenerator) writeInterpreters(out *bytes.Buffer) {
+	if len(cg.interpreters) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(cg.interpreters))
+	for name := range cg.interpreters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(out, "// Interpreters contains the interpreters used in the grammar\n")
+	fmt.Fprintf(out, "type Interpreters interface {\n")
+	for _, name := range names {
+		fmt.Fprintf(out, "\t%s(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error)\n", cg.interpreters[name])
+	}
+	fmt.Fprintf(out, "}\n\n")
+
+	fmt.Fprintf(out, "// UnimplementedInterpreters returns an error for all interpreters\n")
+	fmt.Fprintf(out, "// It can be embedded in an Interpreters implementation to only implement some of the interpreters.\n")
+	fmt.Fprintf(out, "type UnimplementedInterpreters struct{}\n\n")
+	for _, name := range names {
+		goName := cg.interpreters[name]
+		fmt.Fprintf(out, "// %s interprets the sequences bound to %q\n", goName, name)
+		fmt.Fprintf(out, "func (UnimplementedInterpreters) %s(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {\n", goName)
+		fmt.Fprintf(out, "\treturn nil, parsley.NewErrorf(nodes[0].Pos(), \"interpreter %%q is not implemented\", %q)\n}\n\n", name)
+	}
+}
+
+// argList returns with the arguments on separate lines
+func argList(args []string) string {
+	return "\n" + strings.Join(args, ",\n") + ",\n"
+}
+
+// toGoName converts a grammar name to an exported Go name, e.g. "key_value" will be "KeyValue"
+func toGoName(name string) string {
+	var res string
+	for _, part := range goNameSeparatorRegexp.Split(name, -1) {
+		if part != "" {
+			res += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	if res == "" {
+		return "X" + name
+	}
+	return res
+}
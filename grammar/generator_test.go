@@ -0,0 +1,144 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package grammar_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/grammar"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+var _ = Describe("Generator", func() {
+
+	var (
+		generator *grammar.Generator
+		src       string
+		code      string
+	)
+
+	BeforeEach(func() {
+		generator = grammar.NewGenerator("calc")
+		src = `
+			sum       = sum "+" integer -> add | integer ;
+			key_value = ` + "`[a-z]+`" + ` ":" [ value ] -> select(2) ;
+			value     = "true" / "false" / string -> nil ;
+		`
+	})
+
+	JustBeforeEach(func() {
+		res, err := generator.Generate(text.NewFile("calc.ebnf", []byte(src)))
+		Expect(err).ToNot(HaveOccurred())
+		code = string(res)
+	})
+
+	It("should generate valid Go code", func() {
+		f, err := parser.ParseFile(token.NewFileSet(), "calc_parser.go", code, parser.ParseComments)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(f.Name.Name).To(Equal("calc"))
+		Expect(code).To(HavePrefix("// Code generated by parsleygen from calc.ebnf. DO NOT EDIT.\n"))
+	})
+
+	It("should generate a field for every rule", func() {
+		Expect(code).To(ContainSubstring("\tSum      *parser.NamedFunc\n\tKeyValue *parser.NamedFunc\n\tValue    *parser.NamedFunc\n"))
+		Expect(code).To(ContainSubstring(`KeyValue: parser.Func(nil).WithName("key_value"),`))
+	})
+
+	It("should generate the interpreter interface and the stubs", func() {
+		Expect(code).To(ContainSubstring("type Interpreters interface {\n\tAdd(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error)\n}"))
+		Expect(code).To(ContainSubstring("func (UnimplementedInterpreters) Add("))
+		Expect(code).To(ContainSubstring("func NewParsers(i Interpreters) *Parsers {"))
+		Expect(code).To(ContainSubstring(").Bind(ast.InterpreterFunc(i.Add)),"))
+	})
+
+	It("should generate the same parsers as the loader", func() {
+		Expect(code).To(ContainSubstring(`*p.Sum = *combinator.Memoize(combinator.Any("sum",`))
+		Expect(code).To(ContainSubstring(`text.LeftTrim(terminal.Integer(), text.WsSpacesNl),`))
+		Expect(code).To(ContainSubstring(`text.LeftTrim(terminal.Regexp("REGEXP", "` + "`[a-z]+`" + `", "[a-z]+", 0), text.WsSpacesNl),`))
+		Expect(code).To(ContainSubstring(`combinator.Optional(p.Value),`))
+		Expect(code).To(ContainSubstring(").Bind(interpreter.Select(2))"))
+		Expect(code).To(ContainSubstring(`combinator.Choice("value",`))
+		Expect(code).To(ContainSubstring(`text.LeftTrim(terminal.Word("true", "true"), text.WsSpacesNl),`))
+		Expect(code).To(ContainSubstring(").Bind(interpreter.Nil())"))
+		Expect(code).To(ContainSubstring("return combinator.Sentence(text.RightTrim(p.Sum, text.WsSpacesNl))"))
+	})
+
+	Context("when no custom interpreters are used", func() {
+		BeforeEach(func() {
+			src = `a = "x" ;`
+		})
+
+		It("should not generate the interpreter interface", func() {
+			Expect(code).ToNot(ContainSubstring("Interpreters"))
+			Expect(code).ToNot(ContainSubstring(`"github.com/sniperkit/snk.fork.parsley/ast"`))
+			Expect(code).To(ContainSubstring("func NewParsers() *Parsers {"))
+			Expect(code).To(ContainSubstring(`*p.A = *combinator.Memoize(text.LeftTrim(terminal.Word("x", "x"), text.WsSpacesNl)).WithName("a")`))
+		})
+	})
+
+	Context("when the whitespace mode is set", func() {
+		BeforeEach(func() {
+			src = `a = "x" ;`
+			generator.WithWsMode(text.WsSpaces)
+		})
+
+		It("should use it for the terminals", func() {
+			Expect(code).To(ContainSubstring(`text.LeftTrim(terminal.Word("x", "x"), text.WsSpaces)`))
+			Expect(code).To(ContainSubstring(`text.RightTrim(p.A, text.WsSpaces)`))
+		})
+	})
+
+	DescribeTable("should return an error for an invalid grammar",
+		func(source string, errMsg string) {
+			_, err := grammar.NewGenerator("test").Generate(text.NewFile("grammar.ebnf", []byte(source)))
+			Expect(err).To(MatchError(errMsg))
+		},
+		Entry("syntax error", "a b", `was expecting "=", "::=" or "<-", found "b"`),
+		Entry("unknown rule", "a = b ;", `unknown rule or parser "b"`),
+		Entry("reserved name", "root = \"x\" ;", `rule "root" has the same Go name as "root": Root`),
+		Entry("same Go name", "a_b = \"x\" ;\naB = \"y\";", `rule "aB" has the same Go name as "a_b": AB`),
+		Entry("same interpreter Go name", "a = \"x\" \"y\" -> a_b | \"z\" \"z\" -> aB ;", `interpreter "aB" has the same Go name as "a_b": AB`),
+	)
+
+	Describe("GenerateFile()", func() {
+		var tmpDir string
+
+		BeforeEach(func() {
+			var tmpErr error
+			tmpDir, tmpErr = ioutil.TempDir("", "parsley-test-")
+			Expect(tmpErr).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		It("should write the generated code", func() {
+			filename := filepath.Join(tmpDir, "test.ebnf")
+			output := filepath.Join(tmpDir, "test_parser.go")
+			ioutil.WriteFile(filename, []byte(`a = "x" ;`), 0600)
+			Expect(grammar.NewGenerator("test").GenerateFile(filename, output)).To(Succeed())
+			res, err := ioutil.ReadFile(output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(res)).To(HavePrefix("// Code generated by parsleygen from test.ebnf. DO NOT EDIT.\n\npackage test\n"))
+		})
+
+		It("should return an error with the position", func() {
+			filename := filepath.Join(tmpDir, "test.ebnf")
+			ioutil.WriteFile(filename, []byte("a = \"x\" ;\nb = c ;"), 0600)
+			err := grammar.NewGenerator("test").GenerateFile(filename, filepath.Join(tmpDir, "test_parser.go"))
+			Expect(err).To(MatchError(filename + `:2:5: unknown rule or parser "c"`))
+		})
+	})
+})
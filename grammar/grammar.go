@@ -0,0 +1,50 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Package grammar loads grammars from an EBNF/PEG-like text definition at runtime
+//
+// The rules are compiled into the combinator and text/terminal parsers:
+//
+//	// comments are written with // or (* *)
+//	expr    = expr "+" integer -> add | integer ;   (* "|" tries all alternatives and handles left recursion *)
+//	value   = "true" / "false" / word ;               (* "/" is the PEG ordered choice, the first match wins *)
+//	word    = `[a-z]+` ;                              (* regular expressions are written between backquotes *)
+//	list    = "[" [ value { "," value } ] "]" -> list ;
+//
+// The items of a sequence can be followed by "*" (zero or more), "+" (one or more) or "?" (optional). The EBNF
+// [ ] (optional) and { } (zero or more) groups are also supported. A sequence can be bound to a named interpreter
+// registered in the Loader with "-> name". The rules can be defined with "=", "::=" or "<-" and the ";" terminator is
+// optional. Every terminal skips the whitespaces before it.
+package grammar
+
+import (
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+// Grammar contains the compiled rules of a grammar
+type Grammar struct {
+	rules  map[string]parsley.Parser
+	names  []string
+	wsMode text.WsMode
+}
+
+// Rule returns with the parser for the given rule
+func (g *Grammar) Rule(name string) (parsley.Parser, bool) {
+	p, ok := g.rules[name]
+	return p, ok
+}
+
+// RuleNames returns with the rule names in the order of their definition
+func (g *Grammar) RuleNames() []string {
+	return append([]string{}, g.names...)
+}
+
+// Root returns with a parser which matches the whole input using the first rule
+// The trailing whitespaces are skipped and the value of the result is the value of the first rule.
+func (g *Grammar) Root() parsley.Parser {
+	return combinator.Sentence(text.RightTrim(g.rules[g.names[0]], g.wsMode))
+}
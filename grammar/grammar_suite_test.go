@@ -0,0 +1,18 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package grammar_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGrammar(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Grammar Suite")
+}
@@ -0,0 +1,252 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sniperkit/snk.fork.parsley/ast/interpreter"
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+var errEmptyLiteral = errors.New("literal can not be empty")
+
+var wordRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+var selectRegexp = regexp.MustCompile(`^select\(([0-9]+)\)$`)
+
+// Loader compiles grammar definitions using the registered interpreters and parsers
+// The "string", "integer", "float" and "char" parsers and the "array", "object", "nil" and "select(N)"
+// interpreters are available by default.
+type Loader struct {
+	interpreters map[string]parsley.Interpreter
+	parsers      map[string]parsley.Parser
+	wsMode       text.WsMode
+}
+
+// NewLoader creates a new grammar loader
+// The terminals will skip spaces, tabs and new lines by default.
+func NewLoader() *Loader {
+	return &Loader{
+		interpreters: map[string]parsley.Interpreter{
+			"array":  interpreter.Array(),
+			"object": interpreter.Object(),
+			"nil":    interpreter.Nil(),
+		},
+		parsers: map[string]parsley.Parser{
+			"string":  terminal.String(false),
+			"integer": terminal.Integer(),
+			"float":   terminal.Float(),
+			"char":    terminal.Char(),
+		},
+		wsMode: text.WsSpacesNl,
+	}
+}
+
+// RegisterInterpreter registers an interpreter which can be bound to sequences with "-> name"
+func (l *Loader) RegisterInterpreter(name string, i parsley.Interpreter) *Loader {
+	l.interpreters[name] = i
+	return l
+}
+
+// RegisterParser registers a parser which can be referenced by name in the rules
+// The rules with the same name take precedence over the registered parsers.
+func (l *Loader) RegisterParser(name string, p parsley.Parser) *Loader {
+	l.parsers[name] = p
+	return l
+}
+
+// WithWsMode sets which whitespaces are skipped before the terminals
+func (l *Loader) WithWsMode(wsMode text.WsMode) *Loader {
+	l.wsMode = wsMode
+	return l
+}
+
+// Load parses the grammar definition in the given file and compiles the rules
+// The error positions can be resolved with a file set containing the file.
+func (l *Loader) Load(f *text.File) (*Grammar, parsley.Error) {
+	g, _, _, err := l.load(f)
+	return g, err
+}
+
+// load parses and compiles the grammar definition, it also returns with the compiler and the rule definitions
+func (l *Loader) load(f *text.File) (*Grammar, *compiler, []*ruleDef, parsley.Error) {
+	sp := &syntaxParser{r: text.NewReader(f), pos: f.Pos(0)}
+	defs, err := sp.parseGrammar()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	c := &compiler{
+		loader: l,
+		rules:  make(map[string]*parser.NamedFunc, len(defs)),
+	}
+	g := &Grammar{
+		rules:  make(map[string]parsley.Parser, len(defs)),
+		wsMode: l.wsMode,
+	}
+	for _, def := range defs {
+		if _, exists := c.rules[def.name]; exists {
+			return nil, nil, nil, parsley.NewErrorf(def.pos, "rule %q is already defined", def.name)
+		}
+		c.rules[def.name] = parser.Func(nil).WithName(def.name)
+		g.rules[def.name] = c.rules[def.name]
+		g.names = append(g.names, def.name)
+	}
+	for _, def := range defs {
+		c.rule = def.name
+		p, err := c.compileRule(def.body)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// the rules are memoized so they can be left-recursive
+		*c.rules[def.name] = *combinator.Memoize(p).WithName(def.name)
+	}
+
+	return g, c, defs, nil
+}
+
+// LoadFile reads and loads the given grammar file
+func (l *Loader) LoadFile(filename string) (*Grammar, error) {
+	f, err := text.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	g, loadErr := l.Load(f)
+	if loadErr != nil {
+		return nil, fmt.Errorf("%s: %s", parsley.NewFileSet(f).Position(loadErr.Pos()), loadErr.Error())
+	}
+	return g, nil
+}
+
+// compiler compiles the grammar expressions to parsers
+type compiler struct {
+	loader *Loader
+	rules  map[string]*parser.NamedFunc
+	rule   string
+}
+
+// compileRule compiles the rule body, the top-level sequence or choice will have the rule's name
+func (c *compiler) compileRule(e expr) (parsley.Parser, parsley.Error) {
+	switch e := e.(type) {
+	case *seqExpr:
+		return c.compileSeq(e, c.rule)
+	case *choiceExpr:
+		return c.compileChoice(e, c.rule)
+	default:
+		return c.compile(e)
+	}
+}
+
+func (c *compiler) compile(e expr) (parsley.Parser, parsley.Error) {
+	switch e := e.(type) {
+	case *refExpr:
+		if p, ok := c.rules[e.name]; ok {
+			return p, nil
+		}
+		if p, ok := c.loader.parsers[e.name]; ok {
+			return text.LeftTrim(p, c.loader.wsMode), nil
+		}
+		return nil, parsley.NewErrorf(e.pos, "unknown rule or parser %q", e.name)
+	case *literalExpr:
+		var p parsley.Parser
+		if wordRegexp.MatchString(e.value) {
+			p = terminal.Word(e.value, e.value)
+		} else {
+			p = terminal.Substring(e.value, e.value, e.value)
+		}
+		return text.LeftTrim(p, c.loader.wsMode), nil
+	case *regexpExpr:
+		p, err := terminal.NewRegexp("REGEXP", "`"+e.expr+"`", e.expr, 0)
+		if err != nil {
+			return nil, parsley.NewErrorf(e.pos, "invalid regular expression: %s", err)
+		}
+		return text.LeftTrim(p, c.loader.wsMode), nil
+	case *seqExpr:
+		return c.compileSeq(e, "")
+	case *choiceExpr:
+		return c.compileChoice(e, "")
+	case *optionalExpr:
+		p, err := c.compile(e.expr)
+		if err != nil {
+			return nil, err
+		}
+		return combinator.Optional(p), nil
+	case *manyExpr:
+		p, err := c.compile(e.expr)
+		if err != nil {
+			return nil, err
+		}
+		if e.min1 {
+			return combinator.Many1(p), nil
+		}
+		return combinator.Many(p), nil
+	default:
+		panic(fmt.Sprintf("unknown expression type: %T", e))
+	}
+}
+
+func (c *compiler) compileSeq(e *seqExpr, name string) (parsley.Parser, parsley.Error) {
+	parsers := make([]parsley.Parser, len(e.items))
+	for i, item := range e.items {
+		p, err := c.compile(item)
+		if err != nil {
+			return nil, err
+		}
+		parsers[i] = p
+	}
+	seq := combinator.Seq(c.rule, name, parsers...)
+	if e.interpreter != "" {
+		i, err := c.interpreter(e)
+		if err != nil {
+			return nil, err
+		}
+		seq.Bind(i)
+	}
+	return seq, nil
+}
+
+func (c *compiler) compileChoice(e *choiceExpr, name string) (parsley.Parser, parsley.Error) {
+	parsers := make([]parsley.Parser, len(e.alts))
+	names := make([]string, len(e.alts))
+	for i, alt := range e.alts {
+		p, err := c.compile(alt)
+		if err != nil {
+			return nil, err
+		}
+		parsers[i] = p
+		names[i] = p.Name()
+	}
+	if name == "" {
+		name = strings.Join(names, " or ")
+	}
+	if e.ordered {
+		return combinator.Choice(name, parsers...), nil
+	}
+	return combinator.Any(name, parsers...), nil
+}
+
+func (c *compiler) interpreter(e *seqExpr) (parsley.Interpreter, parsley.Error) {
+	if matches := selectRegexp.FindStringSubmatch(e.interpreter); matches != nil {
+		i, _ := strconv.Atoi(matches[1])
+		if i >= len(e.items) {
+			return nil, parsley.NewErrorf(e.pos, "%s is invalid for a sequence of %d items", e.interpreter, len(e.items))
+		}
+		return interpreter.Select(i), nil
+	}
+	if i, ok := c.loader.interpreters[e.interpreter]; ok {
+		return i, nil
+	}
+	return nil, parsley.NewErrorf(e.pos, "unknown interpreter %q", e.interpreter)
+}
@@ -0,0 +1,226 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package grammar_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/grammar"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+// collect returns with the values of the terminal nodes, ignoring the punctuation
+func collect(ctx interface{}, node parsley.Node) ([]interface{}, parsley.Error) {
+	switch n := node.(type) {
+	case *ast.NonTerminalNode:
+		var res []interface{}
+		for _, child := range n.Children() {
+			values, err := collect(ctx, child)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, values...)
+		}
+		return res, nil
+	case *ast.TerminalNode:
+		if n.Token() == "," || n.Token() == ast.NIL {
+			return nil, nil
+		}
+		value, err := n.Value(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{value}, nil
+	default:
+		return nil, nil
+	}
+}
+
+var add = ast.InterpreterFunc(func(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+	v1, err := nodes[0].Value(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v2, err := nodes[2].Value(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return v1.(int) + v2.(int), nil
+})
+
+// Let's load a left-recursive grammar for additions and evaluate an expression.
+func ExampleLoader() {
+	src := `
+		// additions
+		sum = sum "+" integer -> add
+		    | integer ;
+	`
+	g, _ := grammar.NewLoader().
+		RegisterInterpreter("add", add).
+		Load(text.NewFile("sum.ebnf", []byte(src)))
+
+	r := text.NewReader(text.NewFile("example.file", []byte("1 + 2 + 3")))
+	value, _ := parsley.Evaluate(parser.NewHistory(), r, g.Root(), nil)
+	fmt.Printf("%T %v\n", value, value)
+	// Output: int 6
+}
+
+var _ = Describe("Loader", func() {
+
+	var (
+		loader *grammar.Loader
+		src    string
+		g      *grammar.Grammar
+		f      *text.File
+		err    parsley.Error
+	)
+
+	BeforeEach(func() {
+		loader = grammar.NewLoader().RegisterInterpreter("add", add)
+	})
+
+	JustBeforeEach(func() {
+		f = text.NewFile("grammar.ebnf", []byte(src))
+		g, err = loader.Load(f)
+	})
+
+	evaluate := func(input string) (interface{}, parsley.Error) {
+		r := text.NewReader(text.NewFile("input", []byte(input)))
+		return parsley.Evaluate(parser.NewHistory(), r, g.Root(), nil)
+	}
+
+	Context("with a JSON-like grammar", func() {
+		BeforeEach(func() {
+			src = `
+				value  ::= string / float / integer / list / bool / "null" -> nil ;
+				list   ::= "[" [ value { "," value } ] "]" -> list ;
+				bool   ::= "true" / "false" ;
+				(* identifiers are not supported *)
+			`
+			loader.RegisterInterpreter("list", ast.InterpreterFunc(func(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+				return collect(ctx, nodes[1])
+			}))
+		})
+
+		It("should load the rules", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(g.RuleNames()).To(Equal([]string{"value", "list", "bool"}))
+			p, ok := g.Rule("list")
+			Expect(ok).To(BeTrue())
+			Expect(p.Name()).To(Equal("list"))
+			_, ok = g.Rule("other")
+			Expect(ok).To(BeFalse())
+		})
+
+		DescribeTable("should evaluate the input",
+			func(input string, expected interface{}) {
+				Expect(err).ToNot(HaveOccurred())
+				value, evalErr := evaluate(input)
+				Expect(evalErr).ToNot(HaveOccurred())
+				if expected == nil {
+					Expect(value).To(BeNil())
+				} else {
+					Expect(value).To(Equal(expected))
+				}
+			},
+			Entry("string", `"a"`, "a"),
+			Entry("float", `1.5`, 1.5),
+			Entry("integer", ` 12 `, 12),
+			Entry("word", `true`, "true"),
+			Entry("nil", `null`, nil),
+			Entry("empty list", `[]`, []interface{}(nil)),
+			Entry("list", `[1, "b", false]`, []interface{}{1, "b", "false"}),
+		)
+
+		It("should return with an error for an invalid input", func() {
+			_, evalErr := evaluate(`[1,`)
+			Expect(evalErr).To(HaveOccurred())
+		})
+	})
+
+	Context("with PEG-style operators", func() {
+		BeforeEach(func() {
+			src = `
+				words <- word+ "!"? -> select(0)
+				word  <- ` + "`[a-z]+`" + `
+			`
+		})
+
+		It("should compile the repetitions and optional items", func() {
+			Expect(err).ToNot(HaveOccurred())
+			r := text.NewReader(text.NewFile("input", []byte("ab cd !")))
+			node, parseErr := parsley.Parse(parser.NewHistory(), r, g.Root())
+			Expect(parseErr).ToNot(HaveOccurred())
+			Expect(node.Token()).To(Equal("S"))
+		})
+
+		It("should return an error for an invalid input", func() {
+			_, evalErr := evaluate("12")
+			Expect(evalErr).To(MatchError("failed to parse the input: was expecting `[a-z]+`, \"!\" or the end of input, found \"1\""))
+		})
+	})
+
+	DescribeTable("should return an error for an invalid grammar",
+		func(source string, errMsg string, line int, column int) {
+			_, loadErr := grammar.NewLoader().Load(text.NewFile("grammar.ebnf", []byte(source)))
+			Expect(loadErr).To(MatchError(errMsg))
+			position := text.NewFile("grammar.ebnf", []byte(source)).Position(int(loadErr.Pos()) - 1)
+			Expect(position).To(Equal(text.NewPosition("grammar.ebnf", line, column)))
+		},
+		Entry("empty", "  ", "unexpected end of input, was expecting rule definition", 1, 3),
+		Entry("missing assignment", "a b", `was expecting "=", "::=" or "<-", found "b"`, 1, 3),
+		Entry("missing expression", "a = ;", `was expecting expression, found ";"`, 1, 5),
+		Entry("unclosed group", "a = ( b", `unexpected end of input, was expecting ")"`, 1, 8),
+		Entry("invalid primary", "a = #", `was expecting rule name, literal, regular expression or group, found "#"`, 1, 5),
+		Entry("unknown rule", "a = b c ;\nb = \"x\" ;", `unknown rule or parser "c"`, 1, 7),
+		Entry("duplicate rule", "a = \"x\" ;\na = \"y\" ;", `rule "a" is already defined`, 2, 1),
+		Entry("unknown interpreter", "a = \"x\" \"y\" -> foo ;", `unknown interpreter "foo"`, 1, 5),
+		Entry("invalid select", "a = \"x\" -> select(1) ;", `select(1) is invalid for a sequence of 1 items`, 1, 5),
+		Entry("invalid regexp", "a = `[a-` ;", "invalid regular expression: error parsing regexp: missing closing ]: `[a-`", 1, 5),
+		Entry("empty matching regexp", "a = `a*` ;", "invalid regular expression: 'a*' is not allowed to match an empty input", 1, 5),
+		Entry("empty literal", `a = "" ;`, "literal can not be empty", 1, 5),
+		Entry("mixed operators", `a = "x" | "y" / "z" ;`, "the | and / operators can not be mixed without parentheses", 1, 15),
+	)
+
+	Describe("LoadFile()", func() {
+		var tmpDir string
+
+		BeforeEach(func() {
+			var tmpErr error
+			tmpDir, tmpErr = ioutil.TempDir("", "parsley-test-")
+			Expect(tmpErr).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+
+		It("should load the grammar from a file", func() {
+			filename := filepath.Join(tmpDir, "test.ebnf")
+			ioutil.WriteFile(filename, []byte(`a = "x" ;`), 0600)
+			g, loadErr := grammar.NewLoader().LoadFile(filename)
+			Expect(loadErr).ToNot(HaveOccurred())
+			Expect(g.RuleNames()).To(Equal([]string{"a"}))
+		})
+
+		It("should return an error with the position", func() {
+			filename := filepath.Join(tmpDir, "test.ebnf")
+			ioutil.WriteFile(filename, []byte("a = \"x\" ;\nb = c ;"), 0600)
+			_, loadErr := grammar.NewLoader().LoadFile(filename)
+			Expect(loadErr).To(MatchError(filename + `:2:5: unknown rule or parser "c"`))
+		})
+	})
+})
@@ -0,0 +1,330 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package grammar
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+const identExpr = `[a-zA-Z_][a-zA-Z0-9_]*`
+
+var (
+	skipRegexp          = text.MustCompileRegexp(`(?:\s+|//[^\n]*|\(\*(?s:.*?)\*\))+`)
+	identRegexp         = text.MustCompileRegexp(identExpr)
+	ruleStartRegexp     = text.MustCompileRegexp(identExpr + `\s*(?:::=|=|<-)`)
+	numberRegexp        = text.MustCompileRegexp(`[0-9]+`)
+	stringRegexp        = text.MustCompileRegexp(`"(?:[^"\\\n]|\\.)*"`)
+	singleQuotedRegexp  = text.MustCompileRegexp(`'(?:[^'\\\n]|\\.)*'`)
+	regexpLiteralRegexp = text.MustCompileRegexp("`[^`]*`")
+)
+
+// expr is a grammar expression
+type expr interface {
+	Pos() parsley.Pos
+}
+
+type refExpr struct {
+	name string
+	pos  parsley.Pos
+}
+
+type literalExpr struct {
+	value string
+	pos   parsley.Pos
+}
+
+type regexpExpr struct {
+	expr string
+	pos  parsley.Pos
+}
+
+type seqExpr struct {
+	items       []expr
+	interpreter string
+	pos         parsley.Pos
+}
+
+type choiceExpr struct {
+	alts    []expr
+	ordered bool
+	pos     parsley.Pos
+}
+
+type optionalExpr struct {
+	expr expr
+	pos  parsley.Pos
+}
+
+type manyExpr struct {
+	expr expr
+	min1 bool
+	pos  parsley.Pos
+}
+
+func (e *refExpr) Pos() parsley.Pos      { return e.pos }
+func (e *literalExpr) Pos() parsley.Pos  { return e.pos }
+func (e *regexpExpr) Pos() parsley.Pos   { return e.pos }
+func (e *seqExpr) Pos() parsley.Pos      { return e.pos }
+func (e *choiceExpr) Pos() parsley.Pos   { return e.pos }
+func (e *optionalExpr) Pos() parsley.Pos { return e.pos }
+func (e *manyExpr) Pos() parsley.Pos     { return e.pos }
+
+// ruleDef is a rule definition
+type ruleDef struct {
+	name string
+	body expr
+	pos  parsley.Pos
+}
+
+// syntaxParser is a hand-written recursive descent parser for the grammar definitions
+type syntaxParser struct {
+	r   *text.Reader
+	pos parsley.Pos
+}
+
+func (p *syntaxParser) skip() {
+	p.pos, _ = p.r.ReadCompiledRegexp(p.pos, skipRegexp)
+}
+
+func (p *syntaxParser) expected(expected ...string) parsley.Error {
+	return parsley.DescribeError(p.r, parsley.NewExpectedError(p.pos, expected...))
+}
+
+func (p *syntaxParser) match(str string) bool {
+	p.skip()
+	pos, ok := p.r.MatchString(p.pos, str)
+	if ok {
+		p.pos = pos
+	}
+	return ok
+}
+
+func (p *syntaxParser) ident() (string, bool) {
+	p.skip()
+	pos, res := p.r.ReadCompiledRegexp(p.pos, identRegexp)
+	if res == nil {
+		return "", false
+	}
+	p.pos = pos
+	return string(res), true
+}
+
+// parseGrammar parses all the rule definitions
+func (p *syntaxParser) parseGrammar() ([]*ruleDef, parsley.Error) {
+	var rules []*ruleDef
+	for {
+		p.skip()
+		if p.r.IsEOF(p.pos) {
+			break
+		}
+		rule, err := p.parseRule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return nil, p.expected("rule definition")
+	}
+	return rules, nil
+}
+
+func (p *syntaxParser) parseRule() (*ruleDef, parsley.Error) {
+	p.skip()
+	pos := p.pos
+	name, ok := p.ident()
+	if !ok {
+		return nil, p.expected("rule name")
+	}
+	if !p.match("::=") && !p.match("=") && !p.match("<-") {
+		return nil, p.expected(`"="`, `"::="`, `"<-"`)
+	}
+	body, err := p.parseChoice()
+	if err != nil {
+		return nil, err
+	}
+	p.match(";")
+	return &ruleDef{name: name, body: body, pos: pos}, nil
+}
+
+func (p *syntaxParser) parseChoice() (expr, parsley.Error) {
+	p.skip()
+	pos := p.pos
+	first, err := p.parseSeq()
+	if err != nil {
+		return nil, err
+	}
+	alts := []expr{first}
+	op := ""
+	for {
+		p.skip()
+		opPos := p.pos
+		var next string
+		switch {
+		case p.match("|"):
+			next = "|"
+		case p.match("/"):
+			next = "/"
+		default:
+			if len(alts) == 1 {
+				return first, nil
+			}
+			return &choiceExpr{alts: alts, ordered: op == "/", pos: pos}, nil
+		}
+		if op != "" && op != next {
+			return nil, parsley.NewErrorf(opPos, "the | and / operators can not be mixed without parentheses")
+		}
+		op = next
+		alt, err := p.parseSeq()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, alt)
+	}
+}
+
+func (p *syntaxParser) parseSeq() (expr, parsley.Error) {
+	p.skip()
+	pos := p.pos
+	var items []expr
+	for {
+		p.skip()
+		if p.atSeqEnd() {
+			break
+		}
+		item, err := p.parseItem()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil, p.expected("expression")
+	}
+
+	interpreter := ""
+	if p.match("->") {
+		var ok bool
+		if interpreter, ok = p.ident(); !ok {
+			return nil, p.expected("interpreter name")
+		}
+		if p.match("(") {
+			p.skip()
+			argPos, arg := p.r.ReadCompiledRegexp(p.pos, numberRegexp)
+			if arg == nil {
+				return nil, p.expected("interpreter argument")
+			}
+			p.pos = argPos
+			if !p.match(")") {
+				return nil, p.expected(`")"`)
+			}
+			interpreter += "(" + string(arg) + ")"
+		}
+	}
+
+	if len(items) == 1 && interpreter == "" {
+		return items[0], nil
+	}
+	return &seqExpr{items: items, interpreter: interpreter, pos: pos}, nil
+}
+
+// atSeqEnd returns true if the sequence ends at the current position
+func (p *syntaxParser) atSeqEnd() bool {
+	if p.r.IsEOF(p.pos) {
+		return true
+	}
+	for _, s := range []string{"|", "/", ")", "]", "}", ";", "->"} {
+		if _, ok := p.r.MatchString(p.pos, s); ok {
+			return true
+		}
+	}
+	_, ruleStart := p.r.ReadCompiledRegexp(p.pos, ruleStartRegexp)
+	return ruleStart != nil
+}
+
+func (p *syntaxParser) parseItem() (expr, parsley.Error) {
+	p.skip()
+	pos := p.pos
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.match("*"):
+			e = &manyExpr{expr: e, pos: pos}
+		case p.match("+"):
+			e = &manyExpr{expr: e, min1: true, pos: pos}
+		case p.match("?"):
+			e = &optionalExpr{expr: e, pos: pos}
+		default:
+			return e, nil
+		}
+	}
+}
+
+func (p *syntaxParser) parsePrimary() (expr, parsley.Error) {
+	p.skip()
+	pos := p.pos
+
+	if name, ok := p.ident(); ok {
+		return &refExpr{name: name, pos: pos}, nil
+	}
+
+	if readerPos, res := p.r.ReadCompiledRegexp(p.pos, stringRegexp); res != nil {
+		value, err := strconv.Unquote(string(res))
+		if err != nil {
+			return nil, parsley.NewRangeError(pos, readerPos, err)
+		}
+		p.pos = readerPos
+		return p.literal(value, pos, readerPos)
+	}
+
+	if readerPos, res := p.r.ReadCompiledRegexp(p.pos, singleQuotedRegexp); res != nil {
+		value := strings.Replace(string(res[1:len(res)-1]), `\'`, `'`, -1)
+		p.pos = readerPos
+		return p.literal(value, pos, readerPos)
+	}
+
+	if readerPos, res := p.r.ReadCompiledRegexp(p.pos, regexpLiteralRegexp); res != nil {
+		p.pos = readerPos
+		return &regexpExpr{expr: string(res[1 : len(res)-1]), pos: pos}, nil
+	}
+
+	for _, group := range []struct{ open, close string }{{"(", ")"}, {"[", "]"}, {"{", "}"}} {
+		if !p.match(group.open) {
+			continue
+		}
+		e, err := p.parseChoice()
+		if err != nil {
+			return nil, err
+		}
+		if !p.match(group.close) {
+			return nil, p.expected(strconv.Quote(group.close))
+		}
+		switch group.open {
+		case "[":
+			return &optionalExpr{expr: e, pos: pos}, nil
+		case "{":
+			return &manyExpr{expr: e, pos: pos}, nil
+		default:
+			return e, nil
+		}
+	}
+
+	return nil, p.expected("rule name", "literal", "regular expression", "group")
+}
+
+func (p *syntaxParser) literal(value string, pos parsley.Pos, endPos parsley.Pos) (expr, parsley.Error) {
+	if value == "" {
+		return nil, parsley.NewRangeError(pos, endPos, errEmptyLiteral)
+	}
+	return &literalExpr{value: value, pos: pos}, nil
+}
@@ -0,0 +1,83 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// WriteDOT writes the parser graph in the Graphviz DOT format
+// The terminals are rendered as rounded boxes, the memoized rules as bold boxes and the other combinators as ellipses
+// with their kind and name. The edges of the sequences and ordered choices are numbered. The wrapper parsers (e.g.
+// text.LeftTrim) are not displayed.
+func WriteDOT(w io.Writer, p parsley.Parser) error {
+	ids := map[parsley.Parser]string{}
+	count := 0
+	var nodes, edges bytes.Buffer
+
+	var visit func(p parsley.Parser) string
+	visit = func(p parsley.Parser) string {
+		p = unwrap(p)
+		if isPointer(p) {
+			if id, ok := ids[p]; ok {
+				return id
+			}
+		}
+		id := fmt.Sprintf("n%d", count)
+		count++
+		if isPointer(p) {
+			ids[p] = id
+		}
+
+		kind := parsley.KindOf(p)
+		switch kind {
+		case parsley.KindTerminal:
+			fmt.Fprintf(&nodes, "\t%s [label=%s, shape=box, style=rounded];\n", id, dotString(p.Name()))
+		case parsley.KindMemoize:
+			fmt.Fprintf(&nodes, "\t%s [label=%s, shape=box, style=bold];\n", id, dotString(p.Name()))
+		default:
+			label := string(kind)
+			if name := p.Name(); name != "" {
+				label += "\n" + name
+			}
+			fmt.Fprintf(&nodes, "\t%s [label=%s, shape=ellipse];\n", id, dotString(label))
+		}
+
+		children := parsley.ChildrenOf(p)
+		for i, child := range children {
+			childID := visit(child)
+			switch kind {
+			case parsley.KindSeq, parsley.KindSeqTry, parsley.KindChoice:
+				fmt.Fprintf(&edges, "\t%s -> %s [label=\"%d\"];\n", id, childID, i+1)
+			default:
+				fmt.Fprintf(&edges, "\t%s -> %s;\n", id, childID)
+			}
+		}
+		return id
+	}
+	visit(p)
+
+	var out bytes.Buffer
+	out.WriteString("digraph grammar {\n")
+	out.Write(nodes.Bytes())
+	out.Write(edges.Bytes())
+	out.WriteString("}\n")
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// dotString returns with a quoted DOT string
+func dotString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return `"` + s + `"`
+}
@@ -0,0 +1,45 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/introspect"
+)
+
+var _ = Describe("WriteDOT", func() {
+	It("should write the parser graph", func() {
+		var b bytes.Buffer
+		Expect(introspect.WriteDOT(&b, newArrayParser())).To(Succeed())
+		Expect(b.String()).To(Equal(`digraph grammar {
+	n0 [label="seq\nvalue", shape=ellipse];
+	n1 [label="value", shape=box, style=bold];
+	n2 [label="choice\nvalue", shape=ellipse];
+	n3 [label="integer value", shape=box, style=rounded];
+	n4 [label="seq\narray", shape=ellipse];
+	n5 [label="\"[\"", shape=box, style=rounded];
+	n6 [label="sep_by\nvalues separated by \",\"", shape=ellipse];
+	n7 [label="\",\"", shape=box, style=rounded];
+	n8 [label="\"]\"", shape=box, style=rounded];
+	n9 [label="the end of input", shape=box, style=rounded];
+	n2 -> n3 [label="1"];
+	n4 -> n5 [label="1"];
+	n6 -> n1;
+	n6 -> n7;
+	n4 -> n6 [label="2"];
+	n4 -> n8 [label="3"];
+	n2 -> n4 [label="2"];
+	n1 -> n2;
+	n0 -> n1 [label="1"];
+	n0 -> n9 [label="2"];
+}
+`))
+	})
+})
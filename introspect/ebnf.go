@@ -0,0 +1,256 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+var nonIdentifierRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// expression precedence levels
+const (
+	precChoice = iota
+	precSeq
+	precAtom
+)
+
+// docRule is a rule in the generated documentation
+type docRule struct {
+	name string
+	p    parsley.Parser
+}
+
+// WriteEBNF writes the grammar in the ISO EBNF notation
+// A rule is written for the given parser and for every memoized rule reachable from it (see Rules). The rule names
+// are derived from the parser names. The literal terminals (e.g. terminal.Word, terminal.Rune or
+// terminal.Substring) are written as quoted strings and all the other terminals are written as special sequences
+// with their name, e.g. "? integer value ?".
+//
+// If the given parser is not a memoized rule (e.g. it's a combinator.Sentence) then it's written as a rule called
+// "root" if its name is already used by an other rule.
+func WriteEBNF(w io.Writer, p parsley.Parser) error {
+	var b bytes.Buffer
+	for _, rule := range docRules(p) {
+		fmt.Fprintf(&b, "%s = %s ;\n", rule.name, newEBNFWriter(rule.p).expr(rule.p, true))
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteMarkdown writes the grammar documentation in Markdown
+// Every rule has a section with its EBNF definition (see WriteEBNF), the list of its alternatives and the list of
+// the terminals used directly in the rule.
+func WriteMarkdown(w io.Writer, p parsley.Parser) error {
+	var b bytes.Buffer
+	for i, rule := range docRules(p) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		ew := newEBNFWriter(rule.p)
+		fmt.Fprintf(&b, "### %s\n\n", rule.name)
+		fmt.Fprintf(&b, "```ebnf\n%s = %s ;\n```\n", rule.name, ew.expr(rule.p, true))
+
+		body := unwrap(rule.p)
+		if parsley.KindOf(body) == parsley.KindMemoize {
+			body = unwrap(parsley.ChildrenOf(body)[0])
+		}
+		if kind := parsley.KindOf(body); kind == parsley.KindChoice || kind == parsley.KindAny {
+			b.WriteString("\nAlternatives:\n")
+			for _, alt := range parsley.ChildrenOf(body) {
+				fmt.Fprintf(&b, "  - `%s`\n", ew.expr(alt, false))
+			}
+		}
+
+		if terminals := ruleTerminals(rule.p); len(terminals) > 0 {
+			b.WriteString("\nTerminals:\n")
+			for _, t := range terminals {
+				fmt.Fprintf(&b, "  - `%s`\n", t)
+			}
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// docRules returns with the rules to document with their names
+func docRules(p parsley.Parser) []docRule {
+	rules := Rules(p)
+	var res []docRule
+	names := map[string]bool{}
+	for _, rule := range rules {
+		names[ruleName(rule.Name())] = true
+	}
+	if len(rules) == 0 || unwrap(p) != rules[0] {
+		name := ruleName(p.Name())
+		if name == "" || names[name] {
+			name = "root"
+		}
+		res = append(res, docRule{name: name, p: p})
+	}
+	for _, rule := range rules {
+		res = append(res, docRule{name: ruleName(rule.Name()), p: rule})
+	}
+	return res
+}
+
+// ruleTerminals returns with the distinct terminals used directly in the rule (without the referenced rules)
+func ruleTerminals(p parsley.Parser) []string {
+	var res []string
+	seen := map[string]bool{}
+	root := unwrap(p)
+	Walk(p, func(child parsley.Parser) bool {
+		switch parsley.KindOf(child) {
+		case parsley.KindMemoize:
+			return child == root
+		case parsley.KindTerminal:
+			t := terminal(child)
+			if !seen[t] {
+				seen[t] = true
+				res = append(res, t)
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// ruleName converts the parser name to an EBNF identifier
+func ruleName(name string) string {
+	return strings.Trim(nonIdentifierRegexp.ReplaceAllString(name, "_"), "_")
+}
+
+// terminal returns with the EBNF representation of a terminal
+// The literal parsers have a Go-quoted name, so these are written as EBNF strings.
+func terminal(p parsley.Parser) string {
+	name := p.Name()
+	if literal, err := strconv.Unquote(name); err == nil && strings.HasPrefix(name, `"`) && literal != "" {
+		return quoteLiteral(literal)
+	}
+	return "? " + name + " ?"
+}
+
+// quoteLiteral returns with the literal as an EBNF string
+// EBNF strings can't contain their own quote character, so a literal containing both quote characters is written as
+// a concatenation of strings in parentheses, e.g. a'b"c is written as ( "a'b" , '"c' ).
+func quoteLiteral(literal string) string {
+	var parts []string
+	start := 0
+	hasSingle, hasDouble := false, false
+	for i, c := range literal {
+		if c == '"' && hasSingle || c == '\'' && hasDouble {
+			parts = append(parts, quoteString(literal[start:i]))
+			start = i
+			hasSingle, hasDouble = false, false
+		}
+		hasSingle = hasSingle || c == '\''
+		hasDouble = hasDouble || c == '"'
+	}
+	parts = append(parts, quoteString(literal[start:]))
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "( " + strings.Join(parts, " , ") + " )"
+}
+
+// quoteString returns with the string in double quotes or in single quotes if it contains a double quote
+func quoteString(s string) string {
+	if strings.Contains(s, `"`) {
+		return "'" + s + "'"
+	}
+	return `"` + s + `"`
+}
+
+// ebnfWriter writes the EBNF expression of a rule
+type ebnfWriter struct {
+	rule  parsley.Parser
+	stack map[parsley.Parser]bool
+}
+
+func newEBNFWriter(rule parsley.Parser) *ebnfWriter {
+	return &ebnfWriter{
+		rule:  unwrap(rule),
+		stack: map[parsley.Parser]bool{},
+	}
+}
+
+// expr returns with the EBNF expression of the parser
+func (ew *ebnfWriter) expr(p parsley.Parser, root bool) string {
+	res, _ := ew.write(p, root)
+	return res
+}
+
+func (ew *ebnfWriter) write(p parsley.Parser, root bool) (string, int) {
+	p = unwrap(p)
+	kind := parsley.KindOf(p)
+	children := parsley.ChildrenOf(p)
+
+	if kind == parsley.KindMemoize && !(root && p == ew.rule) {
+		return ruleName(p.Name()), precAtom
+	}
+	// recursion without memoization
+	if isPointer(p) {
+		if ew.stack[p] {
+			return ruleName(p.Name()), precAtom
+		}
+		ew.stack[p] = true
+		defer delete(ew.stack, p)
+	}
+
+	switch {
+	case kind == parsley.KindTerminal:
+		return terminal(p), precAtom
+	case len(children) == 0:
+		return "? " + p.Name() + " ?", precAtom
+	case kind == parsley.KindMemoize, kind == parsley.KindRecover:
+		return ew.write(children[0], false)
+	case kind == parsley.KindChoice, kind == parsley.KindAny:
+		return ew.join(children, " | ", precChoice), precChoice
+	case kind == parsley.KindOptional:
+		return "[ " + ew.expr(children[0], false) + " ]", precAtom
+	case kind == parsley.KindMany:
+		return "{ " + ew.expr(children[0], false) + " }", precAtom
+	case kind == parsley.KindMany1:
+		return ew.operand(children[0], precSeq) + " , { " + ew.expr(children[0], false) + " }", precSeq
+	case (kind == parsley.KindSepBy || kind == parsley.KindSepBy1) && len(children) == 2:
+		value := ew.operand(children[0], precSeq)
+		res := value + " , { " + ew.operand(children[1], precSeq) + " , " + value + " }"
+		if kind == parsley.KindSepBy {
+			return "[ " + res + " ]", precAtom
+		}
+		return res, precSeq
+	case kind == parsley.KindDependent:
+		return ew.operand(children[0], precSeq) + " , ? " + p.Name() + " ?", precSeq
+	case kind == parsley.KindCount:
+		return "? " + p.Name() + " ?", precAtom
+	default:
+		return ew.join(children, " , ", precSeq), precSeq
+	}
+}
+
+// operand returns with the expression in parentheses if its precedence is lower than the given one
+func (ew *ebnfWriter) operand(p parsley.Parser, prec int) string {
+	res, resPrec := ew.write(p, false)
+	if resPrec < prec {
+		return "( " + res + " )"
+	}
+	return res
+}
+
+func (ew *ebnfWriter) join(parsers []parsley.Parser, sep string, prec int) string {
+	items := make([]string, len(parsers))
+	for i, p := range parsers {
+		items[i] = ew.operand(p, prec)
+	}
+	return strings.Join(items, sep)
+}
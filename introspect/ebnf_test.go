@@ -0,0 +1,84 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect_test
+
+import (
+	"bytes"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/introspect"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+// Let's print the grammar of the integer arrays.
+func ExampleWriteEBNF() {
+	introspect.WriteEBNF(os.Stdout, newArrayParser())
+	// Output:
+	// root = value , ? the end of input ? ;
+	// value = ? integer value ? | "[" , [ value , { "," , value } ] , "]" ;
+}
+
+var _ = Describe("WriteEBNF", func() {
+	write := func(p parsley.Parser) string {
+		var b bytes.Buffer
+		Expect(introspect.WriteEBNF(&b, p)).To(Succeed())
+		return b.String()
+	}
+
+	It("should write the combinators", func() {
+		var item parser.NamedFunc
+		item = *combinator.Memoize(combinator.Seq("ITEM", "item",
+			terminal.Word("key", "key"),
+			combinator.Choice("op", terminal.Rune('='), terminal.Substring("OP", ":=", ":=")),
+			combinator.Many1(combinator.Any("value", terminal.Integer(), terminal.Rune('"'))),
+			combinator.Optional(combinator.Seq("MORE", "", terminal.Rune(';'), &item)),
+			combinator.SepBy1(terminal.Float(), combinator.Choice("sep", terminal.Rune(','), terminal.Rune(';'))),
+			combinator.Many(combinator.Seq("PAIR", "", terminal.Integer(), terminal.Integer())),
+		)).WithName("config item")
+		Expect(write(&item)).To(Equal(
+			`config_item = "key" , ( "=" | ":=" ) , ( ? integer value ? | '"' ) , { ? integer value ? | '"' } , ` +
+				`[ ";" , config_item ] , ? float value ? , { ( "," | ";" ) , ? float value ? } , ` +
+				`{ ? integer value ? , ? integer value ? } ;` + "\n",
+		))
+	})
+
+	It("should use the parser's name for the root if it's not used by a rule", func() {
+		p := combinator.Seq("S", "document", terminal.Word("a", "a"), combinator.Memoize(terminal.Rune('b')).WithName("b"))
+		Expect(write(p)).To(Equal("document = \"a\" , b ;\nb = \"b\" ;\n"))
+	})
+
+	It("should write the literals containing both quote characters", func() {
+		p := combinator.Seq("S", "quotes", terminal.Substring("Q", `a'b"c`, nil), terminal.Substring("Q", `'"'`, nil))
+		Expect(write(p)).To(Equal(`quotes = ( "a'b" , '"c' ) , ( "'" , '"' , "'" ) ;` + "\n"))
+	})
+
+	It("should handle a recursion without memoization", func() {
+		var p combinator.Recursive
+		p = *combinator.Seq("T", "list", terminal.Rune('a'), combinator.Optional(&p))
+		Expect(write(&p)).To(Equal("list = \"a\" , [ list ] ;\n"))
+	})
+})
+
+var _ = Describe("WriteMarkdown", func() {
+	It("should write a section for every rule", func() {
+		var b bytes.Buffer
+		Expect(introspect.WriteMarkdown(&b, newArrayParser())).To(Succeed())
+		Expect(b.String()).To(Equal("### root\n\n" +
+			"```ebnf\nroot = value , ? the end of input ? ;\n```\n\n" +
+			"Terminals:\n  - `? the end of input ?`\n\n" +
+			"### value\n\n" +
+			"```ebnf\nvalue = ? integer value ? | \"[\" , [ value , { \",\" , value } ] , \"]\" ;\n```\n\n" +
+			"Alternatives:\n  - `? integer value ?`\n  - `\"[\" , [ value , { \",\" , value } ] , \"]\"`\n\n" +
+			"Terminals:\n  - `? integer value ?`\n  - `\"[\"`\n  - `\",\"`\n  - `\"]\"`\n",
+		))
+	})
+})
@@ -0,0 +1,18 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestIntrospect(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Introspect Suite")
+}
@@ -0,0 +1,323 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"unicode/utf8"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Railroad diagram dimensions in pixels
+const (
+	rrCharWidth   = 8
+	rrBoxHeight   = 22
+	rrBoxPadding  = 10
+	rrGap         = 10
+	rrArc         = 10
+	rrPadding     = 20
+	rrTitleHeight = 30
+)
+
+const rrStyle = `path { stroke-width: 2; stroke: #333; fill: none; }
+rect { stroke-width: 2; stroke: #333; fill: #f4f4ff; }
+rect.terminal { fill: #f4fff4; }
+text { font: 13px monospace; text-anchor: middle; }
+text.title { font: bold 14px monospace; text-anchor: start; }`
+
+// WriteRailroad writes the railroad diagrams of the grammar as an SVG image
+// A diagram is drawn for the given parser and for every memoized rule reachable from it (see Rules). The references
+// to the rules are drawn as rectangles linking to the rule's diagram, the terminals are drawn as rounded rectangles.
+func WriteRailroad(w io.Writer, p parsley.Parser) error {
+	roots := []parsley.Parser{p}
+	for _, rule := range Rules(p) {
+		if rule != unwrap(p) {
+			roots = append(roots, rule)
+		}
+	}
+
+	var body bytes.Buffer
+	width, height := 0, 0
+	for _, root := range roots {
+		e := newRailroadConverter().convert(root, true)
+		y := height + rrTitleHeight
+		fmt.Fprintf(&body, "<text id=\"%s\" class=\"title\" x=\"%d\" y=\"%d\">%s</text>\n", html.EscapeString(railroadID(root.Name())), rrPadding/2, y-10, html.EscapeString(root.Name()))
+		y += rrPadding + e.up()
+		x := rrPadding
+		fmt.Fprintf(&body, "<path d=\"M%d %dv%d M%d %dh%d\"/>\n", x-10, y-8, 16, x-10, y, 10)
+		e.draw(&body, x, y)
+		x += e.width()
+		fmt.Fprintf(&body, "<path d=\"M%d %dh%d M%d %dv%d\"/>\n", x, y, 10, x+10, y-8, 16)
+		height = y + e.down() + rrPadding
+		if w := e.width() + 2*rrPadding; w > width {
+			width = w
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	fmt.Fprintf(&out, "<style>\n%s\n</style>\n", rrStyle)
+	out.Write(body.Bytes())
+	out.WriteString("</svg>\n")
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// railroadID returns with the element id for the rule's diagram
+func railroadID(name string) string {
+	return "rule-" + name
+}
+
+// railroadConverter converts the parsers to railroad diagram elements
+type railroadConverter struct {
+	stack map[parsley.Parser]bool
+}
+
+func newRailroadConverter() *railroadConverter {
+	return &railroadConverter{stack: map[parsley.Parser]bool{}}
+}
+
+func (c *railroadConverter) convert(p parsley.Parser, root bool) rrElement {
+	p = unwrap(p)
+	kind := parsley.KindOf(p)
+	children := parsley.ChildrenOf(p)
+
+	if kind == parsley.KindMemoize && !root {
+		return &rrBox{text: p.Name(), link: railroadID(p.Name())}
+	}
+	// recursion without memoization
+	if isPointer(p) {
+		if c.stack[p] {
+			return &rrBox{text: p.Name()}
+		}
+		c.stack[p] = true
+		defer delete(c.stack, p)
+	}
+
+	convertAll := func(parsers []parsley.Parser) []rrElement {
+		res := make([]rrElement, len(parsers))
+		for i, child := range parsers {
+			res[i] = c.convert(child, false)
+		}
+		return res
+	}
+
+	switch {
+	case len(children) == 0 && kind != parsley.KindTerminal:
+		return &rrBox{text: p.Name()}
+	case kind == parsley.KindTerminal:
+		return &rrBox{text: p.Name(), terminal: true}
+	case kind == parsley.KindMemoize, kind == parsley.KindRecover:
+		return c.convert(children[0], false)
+	case kind == parsley.KindChoice, kind == parsley.KindAny:
+		return &rrChoice{items: convertAll(children)}
+	case kind == parsley.KindOptional:
+		return &rrChoice{items: []rrElement{&rrSkip{}, c.convert(children[0], false)}}
+	case kind == parsley.KindMany:
+		return &rrChoice{items: []rrElement{&rrSkip{}, &rrLoop{item: c.convert(children[0], false), sep: &rrSkip{}}}}
+	case kind == parsley.KindMany1:
+		return &rrLoop{item: c.convert(children[0], false), sep: &rrSkip{}}
+	case kind == parsley.KindSepBy && len(children) == 2:
+		return &rrChoice{items: []rrElement{&rrSkip{}, &rrLoop{item: c.convert(children[0], false), sep: c.convert(children[1], false)}}}
+	case kind == parsley.KindSepBy1 && len(children) == 2:
+		return &rrLoop{item: c.convert(children[0], false), sep: c.convert(children[1], false)}
+	default:
+		return &rrSequence{items: convertAll(children)}
+	}
+}
+
+// rrElement is a railroad diagram element
+// The elements are entered on the left and exited on the right on the baseline. up and down are the heights above
+// and below the baseline.
+type rrElement interface {
+	width() int
+	up() int
+	down() int
+	draw(b *bytes.Buffer, x int, y int)
+}
+
+// rrSkip is an empty element
+type rrSkip struct{}
+
+func (s *rrSkip) width() int                   { return 0 }
+func (s *rrSkip) up() int                      { return 0 }
+func (s *rrSkip) down() int                    { return 0 }
+func (s *rrSkip) draw(*bytes.Buffer, int, int) {}
+
+// rrBox is a terminal or a rule reference
+type rrBox struct {
+	text     string
+	terminal bool
+	link     string
+}
+
+func (e *rrBox) width() int {
+	return utf8.RuneCountInString(e.text)*rrCharWidth + 2*rrBoxPadding
+}
+
+func (e *rrBox) up() int {
+	return rrBoxHeight / 2
+}
+
+func (e *rrBox) down() int {
+	return rrBoxHeight / 2
+}
+
+func (e *rrBox) draw(b *bytes.Buffer, x int, y int) {
+	if e.link != "" {
+		fmt.Fprintf(b, "<a href=\"#%s\">", html.EscapeString(e.link))
+	}
+	if e.terminal {
+		fmt.Fprintf(b, "<rect class=\"terminal\" x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"10\"/>", x, y-e.up(), e.width(), rrBoxHeight)
+	} else {
+		fmt.Fprintf(b, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/>", x, y-e.up(), e.width(), rrBoxHeight)
+	}
+	fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\">%s</text>", x+e.width()/2, y+4, html.EscapeString(e.text))
+	if e.link != "" {
+		b.WriteString("</a>")
+	}
+	b.WriteString("\n")
+}
+
+// rrSequence draws the items after each other
+type rrSequence struct {
+	items []rrElement
+}
+
+func (e *rrSequence) width() int {
+	w := 0
+	for i, item := range e.items {
+		if i > 0 {
+			w += rrGap
+		}
+		w += item.width()
+	}
+	return w
+}
+
+func (e *rrSequence) up() int {
+	up := 0
+	for _, item := range e.items {
+		if item.up() > up {
+			up = item.up()
+		}
+	}
+	return up
+}
+
+func (e *rrSequence) down() int {
+	down := 0
+	for _, item := range e.items {
+		if item.down() > down {
+			down = item.down()
+		}
+	}
+	return down
+}
+
+func (e *rrSequence) draw(b *bytes.Buffer, x int, y int) {
+	for i, item := range e.items {
+		if i > 0 {
+			fmt.Fprintf(b, "<path d=\"M%d %dh%d\"/>\n", x, y, rrGap)
+			x += rrGap
+		}
+		item.draw(b, x, y)
+		x += item.width()
+	}
+}
+
+// rrChoice draws the items below each other, the first item is on the baseline
+type rrChoice struct {
+	items []rrElement
+}
+
+func (e *rrChoice) innerWidth() int {
+	w := 0
+	for _, item := range e.items {
+		if item.width() > w {
+			w = item.width()
+		}
+	}
+	return w
+}
+
+func (e *rrChoice) width() int {
+	return e.innerWidth() + 4*rrArc
+}
+
+func (e *rrChoice) up() int {
+	return e.items[0].up()
+}
+
+func (e *rrChoice) down() int {
+	down := e.items[0].down()
+	for _, item := range e.items[1:] {
+		down += rrGap + item.up() + item.down()
+	}
+	return down
+}
+
+func (e *rrChoice) draw(b *bytes.Buffer, x int, y int) {
+	w := e.width()
+	itemX := x + 2*rrArc
+	end := x + w - 2*rrArc
+
+	first := e.items[0]
+	fmt.Fprintf(b, "<path d=\"M%d %dh%d\"/>\n", x, y, 2*rrArc)
+	first.draw(b, itemX, y)
+	fmt.Fprintf(b, "<path d=\"M%d %dH%d\"/>\n", itemX+first.width(), y, x+w)
+
+	itemY := y + first.down()
+	for _, item := range e.items[1:] {
+		itemY += rrGap + item.up()
+		fmt.Fprintf(b, "<path d=\"M%d %dq%d 0 %d %dV%dq0 %d %d %d\"/>\n", x, y, rrArc, rrArc, rrArc, itemY-rrArc, rrArc, rrArc, rrArc)
+		item.draw(b, itemX, itemY)
+		fmt.Fprintf(b, "<path d=\"M%d %dH%dq%d 0 %d %dV%dq0 %d %d %d\"/>\n", itemX+item.width(), itemY, end, rrArc, rrArc, -rrArc, y+rrArc, -rrArc, rrArc, -rrArc)
+		itemY += item.down()
+	}
+}
+
+// rrLoop draws the item on the baseline and a loop back below it through the separator
+type rrLoop struct {
+	item rrElement
+	sep  rrElement
+}
+
+func (e *rrLoop) width() int {
+	w := e.item.width()
+	if e.sep.width() > w {
+		w = e.sep.width()
+	}
+	return w + 4*rrArc
+}
+
+func (e *rrLoop) up() int {
+	return e.item.up()
+}
+
+func (e *rrLoop) down() int {
+	return e.item.down() + rrGap + e.sep.up() + e.sep.down()
+}
+
+func (e *rrLoop) draw(b *bytes.Buffer, x int, y int) {
+	w := e.width()
+	itemX := x + 2*rrArc
+	end := x + w - 2*rrArc
+
+	fmt.Fprintf(b, "<path d=\"M%d %dh%d\"/>\n", x, y, 2*rrArc)
+	e.item.draw(b, itemX, y)
+	fmt.Fprintf(b, "<path d=\"M%d %dH%d\"/>\n", itemX+e.item.width(), y, x+w)
+
+	loopY := y + e.item.down() + rrGap + e.sep.up()
+	fmt.Fprintf(b, "<path d=\"M%d %dq%d 0 %d %dV%dq0 %d %d %d\"/>\n", end, y, rrArc, rrArc, rrArc, loopY-rrArc, rrArc, -rrArc, rrArc)
+	e.sep.draw(b, itemX, loopY)
+	fmt.Fprintf(b, "<path d=\"M%d %dH%d\"/>\n", itemX+e.sep.width(), loopY, end)
+	fmt.Fprintf(b, "<path d=\"M%d %dq%d 0 %d %dV%dq0 %d %d %d\"/>\n", itemX, loopY, -rrArc, -rrArc, -rrArc, y+rrArc, -rrArc, rrArc, -rrArc)
+}
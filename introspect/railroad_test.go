@@ -0,0 +1,66 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/introspect"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+var _ = Describe("WriteRailroad", func() {
+	var (
+		b   bytes.Buffer
+		svg string
+	)
+
+	BeforeEach(func() {
+		b.Reset()
+		Expect(introspect.WriteRailroad(&b, newArrayParser())).To(Succeed())
+		svg = b.String()
+	})
+
+	It("should write a valid SVG document", func() {
+		Expect(svg).To(HavePrefix(`<svg xmlns="http://www.w3.org/2000/svg"`))
+		d := xml.NewDecoder(bytes.NewReader(b.Bytes()))
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+
+	It("should draw a diagram for the root and the rules", func() {
+		Expect(svg).To(ContainSubstring(`<text id="rule-value" class="title" x="10" y="20">value</text>`))
+		Expect(svg).To(ContainSubstring(`<text id="rule-value" class="title" x="10" y="`))
+		Expect(bytes.Count(b.Bytes(), []byte(`class="title"`))).To(Equal(2))
+	})
+
+	It("should draw the terminals and the rule references", func() {
+		Expect(svg).To(ContainSubstring(`<text x="`))
+		Expect(svg).To(ContainSubstring(`>integer value</text>`))
+		Expect(svg).To(ContainSubstring(`>&#34;[&#34;</text>`))
+		Expect(svg).To(ContainSubstring(`<a href="#rule-value"><rect x=`))
+		Expect(svg).To(ContainSubstring(`>the end of input</text>`))
+	})
+
+	It("should handle a recursion without memoization", func() {
+		var p combinator.Recursive
+		p = *combinator.Seq("T", "t", terminal.Rune('a'), combinator.Optional(&p))
+		b.Reset()
+		Expect(introspect.WriteRailroad(&b, &p)).To(Succeed())
+		Expect(b.String()).To(ContainSubstring(`<rect x="`))
+	})
+})
@@ -0,0 +1,356 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// ValidationError is a problem in the grammar found by Validate
+type ValidationError struct {
+	// Parser is the parser with the problem
+	Parser parsley.Parser
+	// Msg is the description of the problem
+	Msg string
+}
+
+// Error returns with the error message
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", describeParser(e.Parser), e.Msg)
+}
+
+// Validate checks the grammar for problems which would only be discovered during parsing
+// It reports:
+//   - left-recursive cycles without a memoized parser, which would cause an infinite recursion
+//   - repetitions (Many, Many1, SepBy, SepBy1) of parsers which can match an empty input
+//   - alternatives of a Choice which can never be reached because an earlier alternative always matches, is the same
+//     or is a literal prefix of it
+//   - the problems reported by the parsers implementing parsley.Validator, e.g. an invalid configuration of a custom parser
+//
+// Only the structure exposed through the parsley.Composite interface can be analysed, the parsers which don't
+// implement it are handled as terminals which can't match an empty input.
+func Validate(p parsley.Parser) []*ValidationError {
+	v := &validator{
+		nullable: map[parsley.Parser]bool{},
+	}
+	Walk(p, func(p parsley.Parser) bool {
+		v.parsers = append(v.parsers, p)
+		return true
+	})
+	v.calculateNullable()
+
+	var errs []*ValidationError
+	errs = append(errs, v.leftRecursion()...)
+	for _, p := range v.parsers {
+		if validator, ok := p.(parsley.Validator); ok {
+			if err := validator.Validate(); err != nil {
+				errs = append(errs, &ValidationError{Parser: p, Msg: err.Error()})
+			}
+		}
+		errs = append(errs, v.checkLoop(p)...)
+		errs = append(errs, v.checkChoice(p)...)
+	}
+	return errs
+}
+
+// describeParser returns with the kind and the name of the parser
+func describeParser(p parsley.Parser) string {
+	if name := p.Name(); name != "" {
+		return fmt.Sprintf("%s %q", parsley.KindOf(p), name)
+	}
+	return string(parsley.KindOf(p))
+}
+
+type validator struct {
+	parsers  []parsley.Parser
+	nullable map[parsley.Parser]bool
+}
+
+// calculateNullable calculates which parsers can match an empty input
+// The values are updated until there is no change, so the recursive parsers are also handled.
+func (v *validator) calculateNullable() {
+	for changed := true; changed; {
+		changed = false
+		for _, p := range v.parsers {
+			if !isPointer(p) || v.nullable[p] {
+				continue
+			}
+			if v.calculateNullableFor(p) {
+				v.nullable[p] = true
+				changed = true
+			}
+		}
+	}
+}
+
+func (v *validator) calculateNullableFor(p parsley.Parser) bool {
+	children := parsley.ChildrenOf(p)
+	switch parsley.KindOf(p) {
+	case parsley.KindEmpty, parsley.KindOptional, parsley.KindMany, parsley.KindSepBy, parsley.KindRecover:
+		return true
+	case parsley.KindCount:
+		return isZeroCount(p) || len(children) > 0 && v.isNullable(children[0])
+	case parsley.KindWrapper, parsley.KindMemoize, parsley.KindMany1, parsley.KindSepBy1, parsley.KindSeqTry:
+		return len(children) > 0 && v.isNullable(children[0])
+	case parsley.KindSeq:
+		for _, child := range children {
+			if !v.isNullable(child) {
+				return false
+			}
+		}
+		return len(children) > 0
+	case parsley.KindChoice, parsley.KindAny:
+		for _, child := range children {
+			if v.isNullable(child) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// isZeroCount returns true if the parser applies its child parser zero times, so it always matches an empty input
+func isZeroCount(p parsley.Parser) bool {
+	c, ok := p.(parsley.Counter)
+	if !ok {
+		return false
+	}
+	n, ok := c.Count()
+	return ok && n == 0
+}
+
+func (v *validator) isNullable(p parsley.Parser) bool {
+	if !isPointer(p) {
+		return parsley.KindOf(p) == parsley.KindEmpty
+	}
+	return v.nullable[p]
+}
+
+// alwaysMatches returns true if the parser matches any input, e.g. an optional parser
+func (v *validator) alwaysMatches(p parsley.Parser, visited map[parsley.Parser]bool) bool {
+	if isPointer(p) {
+		if visited[p] {
+			return false
+		}
+		visited[p] = true
+	}
+	children := parsley.ChildrenOf(p)
+	switch parsley.KindOf(p) {
+	case parsley.KindEmpty, parsley.KindOptional, parsley.KindMany, parsley.KindSepBy:
+		return true
+	case parsley.KindCount:
+		return isZeroCount(p)
+	case parsley.KindWrapper, parsley.KindMemoize:
+		return len(children) == 1 && v.alwaysMatches(children[0], visited)
+	case parsley.KindSeq:
+		for _, child := range children {
+			if !v.alwaysMatches(child, visited) {
+				return false
+			}
+		}
+		return len(children) > 0
+	case parsley.KindChoice, parsley.KindAny:
+		for _, child := range children {
+			if v.alwaysMatches(child, visited) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// leftCalls returns with the child parsers which might be called at the same position as the parser
+func (v *validator) leftCalls(p parsley.Parser) []parsley.Parser {
+	children := parsley.ChildrenOf(p)
+	switch parsley.KindOf(p) {
+	case parsley.KindSeq, parsley.KindSeqTry, parsley.KindSepBy, parsley.KindSepBy1:
+		for i, child := range children {
+			if !v.isNullable(child) {
+				return children[:i+1]
+			}
+		}
+		return children
+	case parsley.KindCount:
+		if isZeroCount(p) {
+			return nil
+		}
+		return children[:1]
+	case parsley.KindDependent:
+		return children[:1]
+	default:
+		return children
+	}
+}
+
+// leftRecursion finds the left-recursive cycles which don't contain a memoized parser
+// The strongly connected components of the left call graph are calculated with Tarjan's algorithm, the memoized
+// parsers are left out of the graph as they curtail the left recursion.
+func (v *validator) leftRecursion() []*ValidationError {
+	index := map[parsley.Parser]int{}
+	lowLink := map[parsley.Parser]int{}
+	onStack := map[parsley.Parser]bool{}
+	var stack []parsley.Parser
+	var errs []*ValidationError
+
+	inGraph := func(p parsley.Parser) bool {
+		return isPointer(p) && parsley.KindOf(p) != parsley.KindMemoize
+	}
+
+	var strongConnect func(p parsley.Parser)
+	strongConnect = func(p parsley.Parser) {
+		index[p] = len(index)
+		lowLink[p] = index[p]
+		stack = append(stack, p)
+		onStack[p] = true
+
+		selfLoop := false
+		for _, child := range v.leftCalls(p) {
+			if !inGraph(child) {
+				continue
+			}
+			if child == p {
+				selfLoop = true
+			}
+			if _, visited := index[child]; !visited {
+				strongConnect(child)
+				if lowLink[child] < lowLink[p] {
+					lowLink[p] = lowLink[child]
+				}
+			} else if onStack[child] && index[child] < lowLink[p] {
+				lowLink[p] = index[child]
+			}
+		}
+
+		if lowLink[p] != index[p] {
+			return
+		}
+		var component []parsley.Parser
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == p {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			names := make([]string, 0, len(component)+1)
+			for i := len(component) - 1; i >= 0; i-- {
+				names = append(names, describeParser(component[i]))
+			}
+			names = append(names, describeParser(p))
+			errs = append(errs, &ValidationError{
+				Parser: p,
+				Msg:    "left recursion without memoization: " + strings.Join(names, " -> "),
+			})
+		}
+	}
+
+	for _, p := range v.parsers {
+		if _, visited := index[p]; inGraph(p) && !visited {
+			strongConnect(p)
+		}
+	}
+	return errs
+}
+
+// checkLoop checks whether a repeated parser can match an empty input
+func (v *validator) checkLoop(p parsley.Parser) []*ValidationError {
+	children := parsley.ChildrenOf(p)
+	switch parsley.KindOf(p) {
+	case parsley.KindMany, parsley.KindMany1:
+		if len(children) == 1 && v.isNullable(children[0]) {
+			return []*ValidationError{{Parser: p, Msg: "the repeated parser can match an empty input"}}
+		}
+	case parsley.KindSepBy, parsley.KindSepBy1:
+		if len(children) == 2 && v.isNullable(children[0]) && v.isNullable(children[1]) {
+			return []*ValidationError{{Parser: p, Msg: "the value and the separator parsers can both match an empty input"}}
+		}
+	}
+	return nil
+}
+
+// checkChoice checks whether an alternative of an ordered choice is shadowed by an earlier one
+func (v *validator) checkChoice(p parsley.Parser) []*ValidationError {
+	if parsley.KindOf(p) != parsley.KindChoice {
+		return nil
+	}
+	var errs []*ValidationError
+	alts := parsley.ChildrenOf(p)
+	for j := 1; j < len(alts); j++ {
+		for i := 0; i < j; i++ {
+			if msg := v.shadows(alts[i], alts[j]); msg != "" {
+				errs = append(errs, &ValidationError{
+					Parser: p,
+					Msg:    fmt.Sprintf("alternative %d (%s) is unreachable, %s", j+1, describeParser(alts[j]), msg),
+				})
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// shadows returns with a non-empty reason if the second parser is never reached after the first one
+func (v *validator) shadows(first, second parsley.Parser) string {
+	if isPointer(first) && first == second {
+		return "it's the same as an earlier alternative"
+	}
+	if v.alwaysMatches(first, map[parsley.Parser]bool{}) {
+		return fmt.Sprintf("the earlier %s always matches", describeParser(first))
+	}
+	prefix, word := literal(unwrap(first))
+	if prefix == "" {
+		return ""
+	}
+	lit, _ := firstLiteral(second)
+	if !strings.HasPrefix(lit, prefix) {
+		return ""
+	}
+	if lit == prefix {
+		return fmt.Sprintf("%q is already matched by an earlier alternative", lit)
+	}
+	// a word parser only matches the prefix if it's not followed by a word character
+	if word && isWordChar(prefix[len(prefix)-1]) && isWordChar(lit[len(prefix)]) {
+		return ""
+	}
+	return fmt.Sprintf("the earlier alternative matches its prefix %q", prefix)
+}
+
+// literal returns with the literal string of a terminal and whether it's matched as a word
+// The literal terminals (e.g. terminal.Word, terminal.Rune, terminal.Substring) implement parsley.Literal.
+func literal(p parsley.Parser) (string, bool) {
+	if l, ok := p.(parsley.Literal); ok && parsley.KindOf(p) == parsley.KindTerminal {
+		return l.Literal()
+	}
+	return "", false
+}
+
+// firstLiteral returns with the literal the parser has to start with and whether it's matched as a word
+func firstLiteral(p parsley.Parser) (string, bool) {
+	p = unwrap(p)
+	switch parsley.KindOf(p) {
+	case parsley.KindTerminal:
+		return literal(p)
+	case parsley.KindSeq, parsley.KindSeqTry:
+		if children := parsley.ChildrenOf(p); len(children) > 0 {
+			return firstLiteral(children[0])
+		}
+	}
+	return "", false
+}
+
+func isWordChar(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || b == '_'
+}
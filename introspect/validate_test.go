@@ -0,0 +1,196 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/introspect"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+// Let's validate a left-recursive grammar where the recursion is not memoized.
+func ExampleValidate() {
+	var expr parser.NamedFunc
+	expr = *combinator.Choice("expr",
+		combinator.Seq("SUM", "sum", &expr, terminal.Rune('+'), terminal.Integer()),
+		terminal.Integer(),
+	)
+	for _, err := range introspect.Validate(&expr) {
+		fmt.Println(err)
+	}
+	// Output:
+	// choice "expr": left recursion without memoization: choice "expr" -> seq "sum" -> choice "expr"
+}
+
+// validationErrors returns with the error messages
+func validationErrors(p parsley.Parser) []string {
+	var res []string
+	for _, err := range introspect.Validate(p) {
+		res = append(res, err.Error())
+	}
+	return res
+}
+
+var _ = Describe("Validate", func() {
+	It("should not return errors for a valid grammar", func() {
+		Expect(introspect.Validate(newArrayParser())).To(BeEmpty())
+	})
+
+	It("should allow memoized left recursion", func() {
+		var expr parser.NamedFunc
+		expr = *combinator.Memoize(combinator.Choice("expr",
+			combinator.Seq("SUM", "sum", &expr, terminal.Rune('+'), terminal.Integer()),
+			terminal.Integer(),
+		))
+		Expect(introspect.Validate(&expr)).To(BeEmpty())
+	})
+
+	It("should find the left recursion after nullable parsers", func() {
+		var expr parser.NamedFunc
+		expr = *combinator.Choice("expr",
+			combinator.Seq("SUM", "sum", combinator.Optional(terminal.Rune('-')), &expr, terminal.Rune('+')),
+			terminal.Integer(),
+		)
+		Expect(validationErrors(&expr)).To(Equal([]string{
+			`choice "expr": left recursion without memoization: choice "expr" -> seq "sum" -> choice "expr"`,
+		}))
+	})
+
+	It("should not report right recursion", func() {
+		var expr parser.NamedFunc
+		expr = *combinator.Choice("expr",
+			combinator.Seq("SUM", "sum", terminal.Integer(), terminal.Rune('+'), &expr),
+			terminal.Integer(),
+		)
+		Expect(introspect.Validate(&expr)).To(BeEmpty())
+	})
+
+	It("should report the repetition of nullable parsers", func() {
+		p := combinator.Many(combinator.Optional(terminal.Rune('a')))
+		Expect(validationErrors(p)).To(Equal([]string{
+			`many "one or more \"a\"": the repeated parser can match an empty input`,
+		}))
+	})
+
+	It("should handle a count of zero as nullable", func() {
+		p := combinator.Many(combinator.Count("C", "item", 0, terminal.Rune('a')))
+		Expect(validationErrors(p)).To(Equal([]string{
+			`many "one or more items": the repeated parser can match an empty input`,
+		}))
+		Expect(introspect.Validate(combinator.Many(combinator.Count("C", "a", 2, terminal.Rune('a'))))).To(BeEmpty())
+	})
+
+	It("should find the left recursion after a count of zero", func() {
+		var expr parser.NamedFunc
+		expr = *combinator.Choice("expr",
+			combinator.Seq("SUM", "sum", combinator.Count("C", "nothing", 0, terminal.Rune('-')), &expr, terminal.Rune('+')),
+			terminal.Integer(),
+		)
+		Expect(validationErrors(&expr)).To(Equal([]string{
+			`choice "expr": left recursion without memoization: choice "expr" -> seq "sum" -> choice "expr"`,
+		}))
+	})
+
+	It("should report a separated list where the value and the separator are nullable", func() {
+		p := combinator.SepBy1(combinator.Optional(terminal.Rune('a')), parser.Nil())
+		Expect(validationErrors(p)).To(Equal([]string{
+			`sep_by1 "\"a\" separated by ": the value and the separator parsers can both match an empty input`,
+		}))
+	})
+
+	It("should not report a separated list with a non-nullable separator", func() {
+		p := combinator.SepBy(combinator.Optional(terminal.Rune('a')), terminal.Rune(','))
+		Expect(introspect.Validate(p)).To(BeEmpty())
+	})
+
+	It("should report the alternatives after an always matching alternative", func() {
+		p := combinator.Choice("value", combinator.Optional(terminal.Rune('a')), terminal.Rune('b'))
+		Expect(validationErrors(p)).To(Equal([]string{
+			`choice "value": alternative 2 (terminal "\"b\"") is unreachable, the earlier optional "\"a\"" always matches`,
+		}))
+	})
+
+	It("should report the repeated alternatives", func() {
+		a := terminal.Rune('a')
+		p := combinator.Choice("value", a, terminal.Rune('b'), a)
+		Expect(validationErrors(p)).To(Equal([]string{
+			`choice "value": alternative 3 (terminal "\"a\"") is unreachable, it's the same as an earlier alternative`,
+		}))
+	})
+
+	It("should report the alternatives with the same literal", func() {
+		p := combinator.Choice("value", terminal.Rune('a'), terminal.Rune('a'))
+		Expect(validationErrors(p)).To(Equal([]string{
+			`choice "value": alternative 2 (terminal "\"a\"") is unreachable, "a" is already matched by an earlier alternative`,
+		}))
+	})
+
+	It("should report the alternatives starting with an earlier literal", func() {
+		p := combinator.Choice("operator",
+			terminal.Rune('<'),
+			combinator.Seq("LTE", "lte", terminal.Substring("LTE", "<=", "<="), terminal.Integer()),
+		)
+		Expect(validationErrors(p)).To(Equal([]string{
+			`choice "operator": alternative 2 (seq "lte") is unreachable, the earlier alternative matches its prefix "<"`,
+		}))
+	})
+
+	It("should allow words which are prefixes of later words", func() {
+		p := combinator.Choice("type", terminal.Word("in", "in"), terminal.Word("int", "int"))
+		Expect(introspect.Validate(p)).To(BeEmpty())
+	})
+
+	It("should report substrings which are prefixes of later alternatives", func() {
+		p := combinator.Choice("value", terminal.Substring("A", "a", "a"), terminal.Substring("AB", "ab", "ab"))
+		Expect(validationErrors(p)).To(Equal([]string{
+			`choice "value": alternative 2 (terminal "\"ab\"") is unreachable, the earlier alternative matches its prefix "a"`,
+		}))
+	})
+
+	It("should report substrings which are prefixes of later words", func() {
+		p := combinator.Choice("type", terminal.Substring("IN", "in", "in"), terminal.Word("int", "int"))
+		Expect(validationErrors(p)).To(Equal([]string{
+			`choice "type": alternative 2 (terminal "\"int\"") is unreachable, the earlier alternative matches its prefix "in"`,
+		}))
+	})
+
+	It("should not report terminals which only have a quoted name", func() {
+		p := combinator.Choice("value",
+			terminal.Regexp("A", `"a"`, "a[0-9]", 0),
+			terminal.Substring("AB", "ab", "ab"),
+		)
+		Expect(introspect.Validate(p)).To(BeEmpty())
+	})
+
+	It("should not report shadowed alternatives for Any", func() {
+		p := combinator.Any("value", terminal.Rune('<'), terminal.Substring("LTE", "<=", "<="))
+		Expect(introspect.Validate(p)).To(BeEmpty())
+	})
+
+	It("should report the errors of the parsers implementing parsley.Validator", func() {
+		invalid := func(name string) parsley.Parser {
+			return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+				return nil, nil, data.EmptyIntSet
+			}).WithName(name).WithValidator(func() error {
+				return errors.New("invalid configuration")
+			})
+		}
+		p := combinator.Seq("FOO", "foo", invalid("a"), terminal.Regexp("B", "b", "b+", 0), invalid("c"))
+		errs := introspect.Validate(p)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Error()).To(Equal(`terminal "a": invalid configuration`))
+		Expect(errs[1].Parser.Name()).To(Equal("c"))
+	})
+})
@@ -0,0 +1,69 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Package introspect contains helpers to inspect the structure of a grammar and to export it as a Graphviz graph or
+// as railroad diagrams
+//
+// Only the parsers implementing the parsley.Composite interface (e.g. the combinators) expose their child parsers,
+// all the other parsers are handled as terminals.
+package introspect
+
+import (
+	"reflect"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Walk visits the parser and its children in depth-first order
+// Every parser is visited only once, so recursive grammars can be walked as well. If f returns false then the
+// children of the parser won't be visited.
+func Walk(p parsley.Parser, f func(p parsley.Parser) bool) {
+	walk(p, f, map[parsley.Parser]bool{})
+}
+
+func walk(p parsley.Parser, f func(p parsley.Parser) bool, visited map[parsley.Parser]bool) {
+	if isPointer(p) {
+		if visited[p] {
+			return
+		}
+		visited[p] = true
+	}
+	if !f(p) {
+		return
+	}
+	for _, child := range parsley.ChildrenOf(p) {
+		walk(child, f, visited)
+	}
+}
+
+// Rules returns with the memoized parsers in the order they are found by Walk
+// The memoized parsers are the rules of the grammar, as the recursive rules have to be memoized.
+func Rules(p parsley.Parser) []parsley.Parser {
+	var rules []parsley.Parser
+	Walk(p, func(p parsley.Parser) bool {
+		if parsley.KindOf(p) == parsley.KindMemoize {
+			rules = append(rules, p)
+		}
+		return true
+	})
+	return rules
+}
+
+// unwrap returns with the first parser which is not a wrapper (e.g. the whitespace trimming parsers)
+func unwrap(p parsley.Parser) parsley.Parser {
+	for parsley.KindOf(p) == parsley.KindWrapper {
+		children := parsley.ChildrenOf(p)
+		if len(children) != 1 {
+			break
+		}
+		p = children[0]
+	}
+	return p
+}
+
+// isPointer returns true if the parser can be used as a map key to identify it
+func isPointer(p parsley.Parser) bool {
+	return reflect.ValueOf(p).Kind() == reflect.Ptr
+}
@@ -0,0 +1,107 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package introspect_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/introspect"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+// newArrayParser returns with a recursive parser for integer arrays, e.g. [1,[2,3]]
+func newArrayParser() parsley.Parser {
+	var value parser.NamedFunc
+	array := combinator.Seq("ARRAY", "array",
+		terminal.Rune('['),
+		combinator.SepBy(&value, terminal.Rune(',')),
+		terminal.Rune(']'),
+	)
+	value = *combinator.Memoize(combinator.Choice("value", terminal.Integer(), array))
+	return combinator.Sentence(text.RightTrim(&value, text.WsNone))
+}
+
+// Let's print the structure of a grammar.
+func ExampleWalk() {
+	introspect.Walk(newArrayParser(), func(p parsley.Parser) bool {
+		fmt.Printf("%s: %s\n", parsley.KindOf(p), p.Name())
+		return true
+	})
+	// Output:
+	// seq: value
+	// wrapper: value
+	// memoize: value
+	// choice: value
+	// terminal: integer value
+	// seq: array
+	// terminal: "["
+	// sep_by: values separated by ","
+	// terminal: ","
+	// terminal: "]"
+	// terminal: the end of input
+}
+
+var _ = Describe("Walk", func() {
+	It("should not visit the children if false is returned", func() {
+		var names []string
+		introspect.Walk(newArrayParser(), func(p parsley.Parser) bool {
+			names = append(names, p.Name())
+			return parsley.KindOf(p) != parsley.KindMemoize
+		})
+		Expect(names).To(Equal([]string{"value", "value", "value", "the end of input"}))
+	})
+
+	It("should handle the parsers which are not pointers", func() {
+		var kinds []parsley.ParserKind
+		f := parser.Func(terminal.Integer().Parse)
+		introspect.Walk(combinator.Many(f), func(p parsley.Parser) bool {
+			kinds = append(kinds, parsley.KindOf(p))
+			return true
+		})
+		Expect(kinds).To(Equal([]parsley.ParserKind{parsley.KindMany, parsley.KindTerminal}))
+	})
+})
+
+var _ = Describe("Rules", func() {
+	It("should return the memoized parsers", func() {
+		rules := introspect.Rules(newArrayParser())
+		Expect(rules).To(HaveLen(1))
+		Expect(rules[0].Name()).To(Equal("value"))
+	})
+})
+
+var _ = DescribeTable("combinators should expose their structure",
+	func(p parsley.Parser, kind parsley.ParserKind, childCount int) {
+		Expect(parsley.KindOf(p)).To(Equal(kind))
+		Expect(parsley.ChildrenOf(p)).To(HaveLen(childCount))
+	},
+	Entry("Seq", combinator.Seq("T", "", terminal.Integer(), terminal.Float()), parsley.KindSeq, 2),
+	Entry("SeqTry", combinator.SeqTry("T", "", terminal.Integer(), terminal.Float()), parsley.KindSeqTry, 2),
+	Entry("SeqTry with one parser", combinator.SeqTry("T", "", terminal.Integer()), parsley.KindSeqTry, 1),
+	Entry("Choice", combinator.Choice("c", terminal.Integer(), terminal.Float()), parsley.KindChoice, 2),
+	Entry("Any", combinator.Any("a", terminal.Integer(), terminal.Float()), parsley.KindAny, 2),
+	Entry("Many", combinator.Many(terminal.Integer()), parsley.KindMany, 1),
+	Entry("Many1", combinator.Many1(terminal.Integer()), parsley.KindMany1, 1),
+	Entry("SepBy", combinator.SepBy(terminal.Integer(), terminal.Rune(',')), parsley.KindSepBy, 2),
+	Entry("SepBy1", combinator.SepBy1(terminal.Integer(), terminal.Rune(',')), parsley.KindSepBy1, 2),
+	Entry("Optional", combinator.Optional(terminal.Integer()), parsley.KindOptional, 1),
+	Entry("Memoize", combinator.Memoize(terminal.Integer()), parsley.KindMemoize, 1),
+	Entry("Recover", combinator.Recover(terminal.Integer(), terminal.Rune(';')), parsley.KindRecover, 2),
+	Entry("Count", combinator.Count("T", "", 2, terminal.Integer()), parsley.KindCount, 1),
+	Entry("NewDependent", combinator.NewDependent("T", "", terminal.Integer(), func(interface{}) parsley.Parser { return nil }), parsley.KindDependent, 1),
+	Entry("NewRecursive", combinator.NewRecursive("T", func() string { return "r" }, nil, nil), parsley.KindRecursive, 0),
+	Entry("LeftTrim", text.LeftTrim(terminal.Integer(), text.WsNone), parsley.KindWrapper, 1),
+	Entry("terminal", terminal.Integer(), parsley.KindTerminal, 0),
+	Entry("parser.Func", parser.Func(terminal.Integer().Parse), parsley.KindTerminal, 0),
+)
@@ -0,0 +1,120 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package lexer
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+// Builder is used for defining the lexer rules
+// The rules are added to the current mode, which is DefaultMode at the beginning and can be changed with Mode().
+// The rules are tried in the order they were defined, so the keywords should be added before the identifiers.
+type Builder struct {
+	mode  string
+	modes map[string][]*rule
+	errs  []error
+}
+
+// NewBuilder creates a new lexer builder
+func NewBuilder() *Builder {
+	return &Builder{
+		mode:  DefaultMode,
+		modes: map[string][]*rule{},
+	}
+}
+
+// Mode sets the current mode, the following rules will be added to this mode
+func (b *Builder) Mode(mode string) *Builder {
+	if mode == "" {
+		b.errs = append(b.errs, fmt.Errorf("the mode name can not be empty"))
+	}
+	b.mode = mode
+	return b
+}
+
+// Literal adds a rule matching the given text
+func (b *Builder) Literal(tokenType string, literal string, opts ...RuleOption) *Builder {
+	if literal == "" {
+		b.errs = append(b.errs, fmt.Errorf("literal for %s can not be empty", tokenType))
+	}
+	return b.add(&rule{kind: ruleLiteral, tokenType: tokenType, value: literal}, opts)
+}
+
+// Regexp adds a rule matching the given regular expression
+// The regular expression is always matched at the current position and it must not match an empty string.
+func (b *Builder) Regexp(tokenType string, expr string, opts ...RuleOption) *Builder {
+	rc, err := text.CompileRegexp(expr)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("invalid regexp for %s: %s", tokenType, err))
+	}
+	return b.add(&rule{kind: ruleRegexp, tokenType: tokenType, value: expr, regexp: rc}, opts)
+}
+
+// Keywords adds a rule matching any of the given words
+// A keyword only matches if it's not followed by a word character, so "if" won't match the beginning of "iffy".
+func (b *Builder) Keywords(tokenType string, keywords ...string) *Builder {
+	if len(keywords) == 0 {
+		b.errs = append(b.errs, fmt.Errorf("no keywords were given for %s", tokenType))
+	}
+	for _, keyword := range keywords {
+		if keyword == "" {
+			b.errs = append(b.errs, fmt.Errorf("keyword for %s can not be empty", tokenType))
+		}
+		for i := 0; i < len(keyword); i++ {
+			if keyword[i] >= utf8.RuneSelf {
+				b.errs = append(b.errs, fmt.Errorf("keyword %q for %s must only contain ASCII characters", keyword, tokenType))
+				break
+			}
+		}
+	}
+	return b.add(&rule{kind: ruleKeywords, tokenType: tokenType, keywords: keywords}, nil)
+}
+
+// Skip adds a rule matching the given regular expression where the match is dropped (e.g. whitespaces, comments)
+func (b *Builder) Skip(expr string, opts ...RuleOption) *Builder {
+	rc, err := text.CompileRegexp(expr)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("invalid skip regexp: %s", err))
+	}
+	return b.add(&rule{kind: ruleRegexp, value: expr, regexp: rc, skip: true}, opts)
+}
+
+func (b *Builder) add(r *rule, opts []RuleOption) *Builder {
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.push != "" && r.pop {
+		b.errs = append(b.errs, fmt.Errorf("a rule can not push and pop a mode at the same time"))
+	}
+	b.modes[b.mode] = append(b.modes[b.mode], r)
+	return b
+}
+
+// Build validates the rules and creates the lexer
+func (b *Builder) Build() (*Lexer, error) {
+	if len(b.errs) > 0 {
+		return nil, b.errs[0]
+	}
+	if len(b.modes[DefaultMode]) == 0 {
+		return nil, fmt.Errorf("the %s mode has no rules", DefaultMode)
+	}
+	for mode, rules := range b.modes {
+		for _, r := range rules {
+			if r.push != "" && len(b.modes[r.push]) == 0 {
+				return nil, fmt.Errorf("a rule in the %s mode refers to the undefined %s mode", mode, r.push)
+			}
+		}
+	}
+
+	modes := make(map[string][]*rule, len(b.modes))
+	for mode, rules := range b.modes {
+		modes[mode] = append([]*rule{}, rules...)
+	}
+	return &Lexer{modes: modes}, nil
+}
@@ -0,0 +1,45 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package lexer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/lexer"
+)
+
+var _ = Describe("Builder", func() {
+
+	It("should build a lexer from valid rules", func() {
+		l, err := lexer.NewBuilder().
+			Skip(`\s+`).
+			Literal("+", "+").
+			Regexp("NUMBER", `[0-9]+`).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(l).ToNot(BeNil())
+	})
+
+	DescribeTable("should return an error for invalid rules",
+		func(b *lexer.Builder, errMsg string) {
+			l, err := b.Build()
+			Expect(err).To(MatchError(errMsg))
+			Expect(l).To(BeNil())
+		},
+		Entry("no rules", lexer.NewBuilder(), "the default mode has no rules"),
+		Entry("empty literal", lexer.NewBuilder().Literal("X", ""), "literal for X can not be empty"),
+		Entry("invalid regexp", lexer.NewBuilder().Regexp("X", "[a-"), "invalid regexp for X: error parsing regexp: missing closing ]: `[a-`"),
+		Entry("empty matching regexp", lexer.NewBuilder().Regexp("X", "a*"), "invalid regexp for X: 'a*' is not allowed to match an empty input"),
+		Entry("empty matching skip regexp", lexer.NewBuilder().Skip(`\s*`), `invalid skip regexp: '\s*' is not allowed to match an empty input`),
+		Entry("no keywords", lexer.NewBuilder().Keywords("KEYWORD"), "no keywords were given for KEYWORD"),
+		Entry("non-ASCII keyword", lexer.NewBuilder().Keywords("KEYWORD", "é"), `keyword "é" for KEYWORD must only contain ASCII characters`),
+		Entry("empty mode name", lexer.NewBuilder().Mode("").Literal("X", "x"), "the mode name can not be empty"),
+		Entry("undefined mode", lexer.NewBuilder().Literal("X", "x", lexer.Push("string")), "a rule in the default mode refers to the undefined string mode"),
+		Entry("push and pop", lexer.NewBuilder().Literal("X", "x", lexer.Push("x"), lexer.Pop()), "a rule can not push and pop a mode at the same time"),
+	)
+})
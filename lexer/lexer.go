@@ -0,0 +1,99 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Package lexer contains a declarative lexer builder which turns a text file into a token stream
+// The tokens can be parsed with the token reader and the token terminals.
+package lexer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/token"
+)
+
+// anyCharRegexp matches the next character, it's used for the errors
+var anyCharRegexp = text.MustCompileRegexp("(?s).")
+
+// DefaultMode is the name of the initial lexer mode
+const DefaultMode = "default"
+
+// Lexer splits a text file into tokens using ordered rules
+// In every position the rules of the current mode are tried in the defined order and the first matching rule wins.
+type Lexer struct {
+	modes map[string][]*rule
+}
+
+// Lex reads the whole file and returns with the tokens
+// If no rule matches at a position then an error is returned with the unexpected character. If the end of input is
+// reached in a non-default mode then an unexpected EOF error is returned.
+func (l *Lexer) Lex(f *text.File) ([]token.Token, parsley.Error) {
+	r := text.NewReader(f)
+	tokens := []token.Token{}
+	modes := []string{DefaultMode}
+	pos := f.Pos(0)
+
+	for !r.IsEOF(pos) {
+		mode := modes[len(modes)-1]
+		var matched *rule
+		var readerPos parsley.Pos
+		var value []byte
+		for _, rule := range l.modes[mode] {
+			if readerPos, value = rule.match(r, pos); value != nil {
+				matched = rule
+				break
+			}
+		}
+
+		if matched == nil {
+			endPos, _ := r.ReadCompiledRegexp(pos, anyCharRegexp)
+			return nil, parsley.NewRangeError(pos, endPos, fmt.Errorf("unexpected character %s", r.Describe(pos)))
+		}
+
+		if !matched.skip {
+			tokens = append(tokens, token.NewToken(matched.tokenType, string(value), parsley.NewSpan(pos, readerPos)))
+		}
+
+		switch {
+		case matched.push != "":
+			modes = append(modes, matched.push)
+		case matched.pop:
+			if len(modes) == 1 {
+				return nil, parsley.NewRangeError(pos, readerPos, fmt.Errorf("unexpected %s, not in a nested mode", strconv.Quote(string(value))))
+			}
+			modes = modes[:len(modes)-1]
+		}
+
+		pos = readerPos
+	}
+
+	if len(modes) > 1 {
+		return nil, parsley.NewUnexpectedEOFError(pos, l.popRuleNames(modes[len(modes)-1])...)
+	}
+
+	return tokens, nil
+}
+
+// Reader lexes the file and returns with a token reader
+func (l *Lexer) Reader(f *text.File) (*token.Reader, parsley.Error) {
+	tokens, err := l.Lex(f)
+	if err != nil {
+		return nil, err
+	}
+	return token.NewReader(tokens, f.Pos(f.Len())), nil
+}
+
+// popRuleNames returns with the names of the rules which would leave the given mode
+func (l *Lexer) popRuleNames(mode string) []string {
+	var names []string
+	for _, rule := range l.modes[mode] {
+		if rule.pop {
+			names = append(names, rule.name())
+		}
+	}
+	return names
+}
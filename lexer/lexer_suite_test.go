@@ -0,0 +1,18 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package lexer_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestLexer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Lexer Suite")
+}
@@ -0,0 +1,165 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package lexer_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/lexer"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/token"
+	"github.com/sniperkit/snk.fork.parsley/token/terminal"
+)
+
+// Let's lex and parse a simple assignment
+func ExampleBuilder() {
+	l, _ := lexer.NewBuilder().
+		Skip(`[ \t\r\n]+`).
+		Skip(`//[^\n]*`).
+		Keywords("KEYWORD", "let").
+		Literal("=", "=").
+		Regexp("IDENT", `[a-z]+`).
+		Regexp("NUMBER", `[0-9]+`).
+		Build()
+
+	f := text.NewFile("example.file", []byte("let x = 42 // answer"))
+	r, _ := l.Reader(f)
+
+	p := combinator.Seq("LET", "let statement",
+		terminal.Value("KEYWORD", "let"),
+		terminal.Type("IDENT"),
+		terminal.Value("=", "="),
+		terminal.Type("NUMBER"),
+	)
+	node, _ := parsley.Parse(parser.NewHistory(), r, combinator.Sentence(p))
+	fmt.Println(node)
+	// Output: S{[LET{[KEYWORD{let, 1..5} IDENT{x, 5..7} ={=, 7..9} NUMBER{42, 9..21}], 1..21} EOF{<nil>, 21..21}], 1..21}
+}
+
+var _ = Describe("Lexer", func() {
+
+	var (
+		l     *lexer.Lexer
+		f     *text.File
+		input string
+	)
+
+	BeforeEach(func() {
+		var err error
+		l, err = lexer.NewBuilder().
+			Skip(`[ \t\r\n]+`).
+			Keywords("KEYWORD", "if", "else").
+			Literal("==", "==").
+			Literal("=", "=").
+			Literal("STRING_START", `"`, lexer.Push("string")).
+			Regexp("IDENT", `[a-z]+`).
+			Mode("string").
+			Literal("STRING_END", `"`, lexer.Pop()).
+			Literal("INTERPOLATION_START", "${", lexer.Push(lexer.DefaultMode)).
+			Regexp("STRING_TEXT", `(?:[^"$]|\$[^{])+`).
+			Mode(lexer.DefaultMode).
+			Literal("}", "}", lexer.Pop()).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	lex := func() ([]token.Token, parsley.Error) {
+		f = text.NewFile("testfile", []byte(input))
+		return l.Lex(f)
+	}
+
+	It("should return with the tokens and their positions", func() {
+		input = "if a == b"
+		tokens, err := lex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens).To(Equal([]token.Token{
+			token.NewToken("KEYWORD", "if", parsley.NewSpan(f.Pos(0), f.Pos(2))),
+			token.NewToken("IDENT", "a", parsley.NewSpan(f.Pos(3), f.Pos(4))),
+			token.NewToken("==", "==", parsley.NewSpan(f.Pos(5), f.Pos(7))),
+			token.NewToken("IDENT", "b", parsley.NewSpan(f.Pos(8), f.Pos(9))),
+		}))
+	})
+
+	It("should apply the rules in order", func() {
+		input = "iffy = a"
+		tokens, err := lex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens[0]).To(Equal(token.NewToken("IDENT", "iffy", parsley.NewSpan(f.Pos(0), f.Pos(4)))))
+		Expect(tokens[1].Type).To(Equal("="))
+	})
+
+	It("should return with an empty token list for an empty input", func() {
+		input = ""
+		tokens, err := lex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens).To(BeEmpty())
+	})
+
+	It("should handle the mode stack", func() {
+		input = `"a ${ "b" } c"`
+		tokens, err := lex()
+		Expect(err).ToNot(HaveOccurred())
+		var types []string
+		for _, t := range tokens {
+			types = append(types, t.Type)
+		}
+		Expect(types).To(Equal([]string{
+			"STRING_START", "STRING_TEXT", "INTERPOLATION_START",
+			"STRING_START", "STRING_TEXT", "STRING_END",
+			"}", "STRING_TEXT", "STRING_END",
+		}))
+		Expect(tokens[1].Text).To(Equal("a "))
+	})
+
+	It("should return an error for an unknown character", func() {
+		input = "a\n  #b"
+		_, err := lex()
+		Expect(err).To(MatchError(`unexpected character "#"`))
+		Expect(err.Pos()).To(Equal(f.Pos(4)))
+		Expect(parsley.EndPos(err)).To(Equal(f.Pos(5)))
+		Expect(parsley.NewFileSet(f).Position(err.Pos()).String()).To(Equal("testfile:2:3"))
+	})
+
+	It("should return an unexpected EOF error if a mode is not closed", func() {
+		input = `"abc`
+		_, err := lex()
+		Expect(err).To(MatchError(`unexpected end of input, was expecting "\""`))
+		Expect(err.Pos()).To(Equal(f.Pos(4)))
+		Expect(errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue())
+	})
+
+	It("should return an error if the default mode would be left", func() {
+		input = "a }"
+		_, err := lex()
+		Expect(err).To(MatchError(`unexpected "}", not in a nested mode`))
+		Expect(err.Pos()).To(Equal(f.Pos(2)))
+	})
+
+	Describe("Reader()", func() {
+		It("should return a token reader with the end of file position", func() {
+			f = text.NewFile("testfile", []byte("a "))
+			r, err := l.Reader(f)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Remaining(r.Pos(0))).To(Equal(1))
+			Expect(r.Pos(1)).To(Equal(f.Pos(2)))
+		})
+
+		It("should return the lexer error", func() {
+			f = text.NewFile("testfile", []byte("#"))
+			r, err := l.Reader(f)
+			Expect(err).To(HaveOccurred())
+			Expect(r).To(BeNil())
+		})
+	})
+})
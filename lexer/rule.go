@@ -0,0 +1,78 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package lexer
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+type ruleKind uint8
+
+const (
+	ruleLiteral ruleKind = iota
+	ruleRegexp
+	ruleKeywords
+)
+
+// rule is a lexer rule
+type rule struct {
+	kind      ruleKind
+	tokenType string
+	value     string
+	regexp    *regexp.Regexp
+	keywords  []string
+	skip      bool
+	push      string
+	pop       bool
+}
+
+// RuleOption is an option for a lexer rule
+type RuleOption func(r *rule)
+
+// Push will enter the given mode after the rule matches
+func Push(mode string) RuleOption {
+	return func(r *rule) {
+		r.push = mode
+	}
+}
+
+// Pop will return to the previous mode after the rule matches
+func Pop() RuleOption {
+	return func(r *rule) {
+		r.pop = true
+	}
+}
+
+// match tries to match the rule at the given position and returns with the matched text
+func (r *rule) match(tr *text.Reader, pos parsley.Pos) (parsley.Pos, []byte) {
+	switch r.kind {
+	case ruleLiteral:
+		if readerPos, ok := tr.MatchString(pos, r.value); ok {
+			return readerPos, []byte(r.value)
+		}
+	case ruleRegexp:
+		return tr.ReadCompiledRegexp(pos, r.regexp)
+	default:
+		for _, keyword := range r.keywords {
+			if readerPos, ok := tr.MatchWord(pos, keyword); ok {
+				return readerPos, []byte(keyword)
+			}
+		}
+	}
+	return pos, nil
+}
+
+// name returns with a descriptive name of the rule
+func (r *rule) name() string {
+	if r.kind == ruleLiteral {
+		return strconv.Quote(r.value)
+	}
+	return r.tokenType
+}
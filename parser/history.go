@@ -17,29 +17,51 @@ import (
 )
 
 // History records information about parser calls
+// The results are stored in an unbounded memo store by default, it can be changed with WithMemoStore.
+// A history should be used only for one parsing, it is not safe for concurrent use.
 type History struct {
-	callCount int
-	results   map[int]map[parsley.Pos]*parsley.Result
+	limits      Limits
+	callCount   int
+	resultCount int
+	depth       int
+	store       MemoStore
 }
 
 // NewHistory creates a history instance
 func NewHistory() *History {
+	return NewHistoryWithLimits(Limits{})
+}
+
+// NewHistoryWithLimits creates a history instance which stops the parsing with a LimitError
+// if any of the given limits is exceeded
+func NewHistoryWithLimits(limits Limits) *History {
 	return &History{
-		results: make(map[int]map[parsley.Pos]*parsley.Result),
+		limits: limits,
+		store:  NewUnboundedMemoStore(),
 	}
 }
 
+// WithMemoStore sets the store for the memoized results, it should be called before parsing
+func (h *History) WithMemoStore(store MemoStore) *History {
+	h.store = store
+	return h
+}
+
 // SaveResult registers a parser result for a certain position
-func (h *History) SaveResult(parserIndex int, pos parsley.Pos, result *parsley.Result) {
-	if _, ok := h.results[parserIndex]; !ok {
-		h.results[parserIndex] = make(map[parsley.Pos]*parsley.Result)
+func (h *History) SaveResult(parserIndex int, pos parsley.Pos, result *parsley.Result) parsley.Error {
+	if !h.store.Has(parserIndex, pos) {
+		if h.limits.MaxResults > 0 && h.resultCount >= h.limits.MaxResults {
+			return NewLimitError(LimitResults, h.limits.MaxResults, pos)
+		}
+		h.resultCount++
 	}
-	h.results[parserIndex][pos] = result
+	h.store.Save(parserIndex, pos, result)
+	return nil
 }
 
 // GetResult return with a previously saved result
 func (h *History) GetResult(parserIndex int, pos parsley.Pos, leftRecCtx data.IntMap) (*parsley.Result, bool) {
-	result, found := h.results[parserIndex][pos]
+	result, found := h.store.Get(parserIndex, pos)
 	if !found {
 		return nil, false
 	}
@@ -53,12 +75,37 @@ func (h *History) GetResult(parserIndex int, pos parsley.Pos, leftRecCtx data.In
 	return result, true
 }
 
+// Commit drops the memoized results before the given position if the memo store supports it (e.g. WindowMemoStore)
+func (h *History) Commit(pos parsley.Pos) {
+	if c, ok := h.store.(parsley.Committer); ok {
+		c.Commit(pos)
+	}
+}
+
 // RegisterCall registers a call
-func (h *History) RegisterCall() {
+func (h *History) RegisterCall(pos parsley.Pos) parsley.Error {
 	h.callCount++
+	if h.limits.MaxCalls > 0 && h.callCount > h.limits.MaxCalls {
+		return NewLimitError(LimitCalls, h.limits.MaxCalls, pos)
+	}
+	return nil
 }
 
 // CallCount returns with the call count
 func (h *History) CallCount() int {
 	return h.callCount
 }
+
+// Enter increases the nesting depth
+func (h *History) Enter(pos parsley.Pos) parsley.Error {
+	if h.limits.MaxDepth > 0 && h.depth >= h.limits.MaxDepth {
+		return NewLimitError(LimitDepth, h.limits.MaxDepth, pos)
+	}
+	h.depth++
+	return nil
+}
+
+// Leave decreases the nesting depth
+func (h *History) Leave() {
+	h.depth--
+}
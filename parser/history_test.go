@@ -137,9 +137,94 @@ var _ = Describe("History", func() {
 	Describe("RegisterCall/CallCount", func() {
 		It("should register a call count", func() {
 			Expect(h.CallCount()).To(Equal(0))
-			h.RegisterCall()
+			h.RegisterCall(parsley.Pos(1))
 			Expect(h.CallCount()).To(Equal(1))
 		})
+
+		It("should not return an error", func() {
+			Expect(h.RegisterCall(parsley.Pos(1))).ToNot(HaveOccurred())
+		})
+	})
+
+	Describe("Enter/Leave", func() {
+		It("should not return an error", func() {
+			Expect(h.Enter(parsley.Pos(1))).ToNot(HaveOccurred())
+			h.Leave()
+		})
+	})
+
+	Context("when created with limits", func() {
+		var limits parser.Limits
+
+		BeforeEach(func() {
+			limits = parser.Limits{}
+		})
+
+		JustBeforeEach(func() {
+			h = parser.NewHistoryWithLimits(limits)
+		})
+
+		Context("when the call limit is set", func() {
+			BeforeEach(func() {
+				limits.MaxCalls = 2
+			})
+
+			It("should return a limit error when the limit is exceeded", func() {
+				Expect(h.RegisterCall(parsley.Pos(1))).ToNot(HaveOccurred())
+				Expect(h.RegisterCall(parsley.Pos(2))).ToNot(HaveOccurred())
+				err := h.RegisterCall(parsley.Pos(3))
+				Expect(err).To(MatchError("exceeded the maximum number of parser calls (2)"))
+				Expect(err.Pos()).To(Equal(parsley.Pos(3)))
+				Expect(err).To(BeAssignableToTypeOf(&parser.LimitError{}))
+				Expect(parsley.IsFatal(err)).To(BeTrue())
+			})
+		})
+
+		Context("when the result limit is set", func() {
+			BeforeEach(func() {
+				limits.MaxResults = 2
+			})
+
+			It("should return a limit error when the limit is exceeded", func() {
+				res := &parsley.Result{LeftRecCtx: data.EmptyIntMap}
+				Expect(h.SaveResult(1, parsley.Pos(1), res)).ToNot(HaveOccurred())
+				Expect(h.SaveResult(2, parsley.Pos(1), res)).ToNot(HaveOccurred())
+				err := h.SaveResult(1, parsley.Pos(2), res)
+				Expect(err).To(MatchError("exceeded the maximum number of memoized results (2)"))
+				Expect(err.Pos()).To(Equal(parsley.Pos(2)))
+
+				_, found := h.GetResult(1, parsley.Pos(2), data.EmptyIntMap)
+				Expect(found).To(BeFalse())
+			})
+
+			It("should allow to overwrite an existing result", func() {
+				res := &parsley.Result{LeftRecCtx: data.EmptyIntMap}
+				Expect(h.SaveResult(1, parsley.Pos(1), res)).ToNot(HaveOccurred())
+				Expect(h.SaveResult(2, parsley.Pos(1), res)).ToNot(HaveOccurred())
+				Expect(h.SaveResult(2, parsley.Pos(1), res)).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("when the depth limit is set", func() {
+			BeforeEach(func() {
+				limits.MaxDepth = 2
+			})
+
+			It("should return a limit error when the limit is exceeded", func() {
+				Expect(h.Enter(parsley.Pos(1))).ToNot(HaveOccurred())
+				Expect(h.Enter(parsley.Pos(2))).ToNot(HaveOccurred())
+				err := h.Enter(parsley.Pos(3))
+				Expect(err).To(MatchError("exceeded the maximum nesting depth (2)"))
+				Expect(err.Pos()).To(Equal(parsley.Pos(3)))
+			})
+
+			It("should allow to enter again after leaving", func() {
+				Expect(h.Enter(parsley.Pos(1))).ToNot(HaveOccurred())
+				Expect(h.Enter(parsley.Pos(2))).ToNot(HaveOccurred())
+				h.Leave()
+				Expect(h.Enter(parsley.Pos(3))).ToNot(HaveOccurred())
+			})
+		})
 	})
 
 })
@@ -0,0 +1,70 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package parser
+
+import (
+	"fmt"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Limit names used in LimitError
+const (
+	LimitCalls   = "number of parser calls"
+	LimitResults = "number of memoized results"
+	LimitDepth   = "nesting depth"
+)
+
+// Limits contains the limits enforced by the history during parsing
+// A zero value means there is no limit. MaxResults limits the number of memoized results saved during the parsing,
+// the results dropped by the memo store (e.g. LRUMemoStore) are also counted.
+type Limits struct {
+	MaxCalls   int
+	MaxResults int
+	MaxDepth   int
+}
+
+// LimitError is a fatal error which is returned when one of the history limits is exceeded
+type LimitError struct {
+	Limit string
+	Max   int
+	pos   parsley.Pos
+}
+
+// NewLimitError creates a new limit error
+func NewLimitError(limit string, max int, pos parsley.Pos) *LimitError {
+	return &LimitError{
+		Limit: limit,
+		Max:   max,
+		pos:   pos,
+	}
+}
+
+// Error returns with the error message
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("exceeded the maximum %s (%d)", e.Limit, e.Max)
+}
+
+// Pos returns with the position where the limit was exceeded
+func (e *LimitError) Pos() parsley.Pos {
+	return e.pos
+}
+
+// Cause returns with the error itself as there is no underlying error
+func (e *LimitError) Cause() error {
+	return e
+}
+
+// Fatal returns true as the parsing should stop immediately
+func (e *LimitError) Fatal() bool {
+	return true
+}
@@ -0,0 +1,56 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parser_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+var _ = Describe("LimitError", func() {
+
+	var err *parser.LimitError
+
+	BeforeEach(func() {
+		err = parser.NewLimitError(parser.LimitCalls, 10, parsley.Pos(2))
+	})
+
+	It("implements parsley.Error", func() {
+		var _ parsley.Error = err
+	})
+
+	It("returns with the limit details", func() {
+		Expect(err.Limit).To(Equal(parser.LimitCalls))
+		Expect(err.Max).To(Equal(10))
+	})
+
+	It("returns with the error message", func() {
+		Expect(err.Error()).To(Equal("exceeded the maximum number of parser calls (10)"))
+	})
+
+	It("returns with the position", func() {
+		Expect(err.Pos()).To(Equal(parsley.Pos(2)))
+	})
+
+	It("returns itself as the cause", func() {
+		Expect(err.Cause()).To(BeIdenticalTo(err))
+	})
+
+	It("is a fatal error", func() {
+		Expect(parsley.IsFatal(err)).To(BeTrue())
+	})
+
+	Context("when wrapped", func() {
+		It("keeps the limit error as the cause", func() {
+			wrappedErr := parsley.WrapError(err, "failed: {{err}}")
+			Expect(wrappedErr.Cause()).To(BeIdenticalTo(err))
+			Expect(parsley.IsFatal(wrappedErr)).To(BeTrue())
+		})
+	})
+})
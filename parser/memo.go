@@ -0,0 +1,215 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parser
+
+import (
+	"container/list"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// MemoStore stores the memoized parser results
+// A store is allowed to drop results at any time, the dropped results will be calculated again when needed. This
+// keeps the parsing correct but it might make it slower.
+// Has should return true if a result is stored without any side effects (e.g. without marking it as recently used).
+type MemoStore interface {
+	Get(parserIndex int, pos parsley.Pos) (*parsley.Result, bool)
+	Has(parserIndex int, pos parsley.Pos) bool
+	Save(parserIndex int, pos parsley.Pos, result *parsley.Result)
+	Len() int
+}
+
+// UnboundedMemoStore keeps all the results until the end of the parsing
+type UnboundedMemoStore struct {
+	results map[int]map[parsley.Pos]*parsley.Result
+	count   int
+}
+
+// NewUnboundedMemoStore creates a new unbounded memo store
+func NewUnboundedMemoStore() *UnboundedMemoStore {
+	return &UnboundedMemoStore{
+		results: make(map[int]map[parsley.Pos]*parsley.Result),
+	}
+}
+
+// Get returns with a stored result
+func (s *UnboundedMemoStore) Get(parserIndex int, pos parsley.Pos) (*parsley.Result, bool) {
+	result, found := s.results[parserIndex][pos]
+	return result, found
+}
+
+// Has returns true if a result is stored
+func (s *UnboundedMemoStore) Has(parserIndex int, pos parsley.Pos) bool {
+	_, found := s.results[parserIndex][pos]
+	return found
+}
+
+// Save stores a result
+func (s *UnboundedMemoStore) Save(parserIndex int, pos parsley.Pos, result *parsley.Result) {
+	if _, ok := s.results[parserIndex]; !ok {
+		s.results[parserIndex] = make(map[parsley.Pos]*parsley.Result)
+	}
+	if _, exists := s.results[parserIndex][pos]; !exists {
+		s.count++
+	}
+	s.results[parserIndex][pos] = result
+}
+
+// Len returns with the number of stored results
+func (s *UnboundedMemoStore) Len() int {
+	return s.count
+}
+
+// memoKey identifies a memoized result
+type memoKey struct {
+	parserIndex int
+	pos         parsley.Pos
+}
+
+// lruEntry is a result in the LRU list
+type lruEntry struct {
+	key    memoKey
+	result *parsley.Result
+}
+
+// LRUMemoStore keeps a limited number of results, the least recently used result is dropped first
+type LRUMemoStore struct {
+	size     int
+	elements map[memoKey]*list.Element
+	list     *list.List
+}
+
+// NewLRUMemoStore creates a new LRU memo store which keeps maximum size results
+func NewLRUMemoStore(size int) *LRUMemoStore {
+	if size <= 0 {
+		panic("size must be positive")
+	}
+	return &LRUMemoStore{
+		size:     size,
+		elements: make(map[memoKey]*list.Element, size),
+		list:     list.New(),
+	}
+}
+
+// Get returns with a stored result and marks it as recently used
+func (s *LRUMemoStore) Get(parserIndex int, pos parsley.Pos) (*parsley.Result, bool) {
+	e, found := s.elements[memoKey{parserIndex: parserIndex, pos: pos}]
+	if !found {
+		return nil, false
+	}
+	s.list.MoveToFront(e)
+	return e.Value.(*lruEntry).result, true
+}
+
+// Has returns true if a result is stored, it doesn't mark the result as recently used
+func (s *LRUMemoStore) Has(parserIndex int, pos parsley.Pos) bool {
+	_, found := s.elements[memoKey{parserIndex: parserIndex, pos: pos}]
+	return found
+}
+
+// Save stores a result, if the store is full then the least recently used result is dropped
+func (s *LRUMemoStore) Save(parserIndex int, pos parsley.Pos, result *parsley.Result) {
+	key := memoKey{parserIndex: parserIndex, pos: pos}
+	if e, exists := s.elements[key]; exists {
+		e.Value.(*lruEntry).result = result
+		s.list.MoveToFront(e)
+		return
+	}
+	s.elements[key] = s.list.PushFront(&lruEntry{key: key, result: result})
+	if s.list.Len() > s.size {
+		last := s.list.Back()
+		s.list.Remove(last)
+		delete(s.elements, last.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns with the number of stored results
+func (s *LRUMemoStore) Len() int {
+	return s.list.Len()
+}
+
+// WindowMemoStore keeps the results in a sliding window of positions
+// The results behind the committed position are dropped. The committed position can be set explicitly with Commit
+// and if the window size is positive then it's also moved automatically to size positions behind the furthest
+// saved position.
+type WindowMemoStore struct {
+	size      int
+	results   map[parsley.Pos]map[int]*parsley.Result
+	count     int
+	committed parsley.Pos
+	furthest  parsley.Pos
+}
+
+// NewWindowMemoStore creates a new sliding window memo store
+// If size is zero then the results are only dropped when Commit is called.
+func NewWindowMemoStore(size int) *WindowMemoStore {
+	if size < 0 {
+		panic("size can not be negative")
+	}
+	return &WindowMemoStore{
+		size:    size,
+		results: make(map[parsley.Pos]map[int]*parsley.Result),
+	}
+}
+
+// Get returns with a stored result
+func (s *WindowMemoStore) Get(parserIndex int, pos parsley.Pos) (*parsley.Result, bool) {
+	result, found := s.results[pos][parserIndex]
+	return result, found
+}
+
+// Has returns true if a result is stored
+func (s *WindowMemoStore) Has(parserIndex int, pos parsley.Pos) bool {
+	_, found := s.results[pos][parserIndex]
+	return found
+}
+
+// Save stores a result, the results behind the committed position are not stored
+func (s *WindowMemoStore) Save(parserIndex int, pos parsley.Pos, result *parsley.Result) {
+	if pos < s.committed {
+		return
+	}
+	if _, ok := s.results[pos]; !ok {
+		s.results[pos] = make(map[int]*parsley.Result)
+	}
+	if _, exists := s.results[pos][parserIndex]; !exists {
+		s.count++
+	}
+	s.results[pos][parserIndex] = result
+
+	if pos > s.furthest {
+		s.furthest = pos
+		if s.size > 0 {
+			s.Commit(s.furthest - parsley.Pos(s.size))
+		}
+	}
+}
+
+// Commit drops all the results before the given position
+func (s *WindowMemoStore) Commit(pos parsley.Pos) {
+	if pos <= s.committed {
+		return
+	}
+	if int(pos-s.committed) > len(s.results) {
+		for p, results := range s.results {
+			if p < pos {
+				s.count -= len(results)
+				delete(s.results, p)
+			}
+		}
+	} else {
+		for p := s.committed; p < pos; p++ {
+			s.count -= len(s.results[p])
+			delete(s.results, p)
+		}
+	}
+	s.committed = pos
+}
+
+// Len returns with the number of stored results
+func (s *WindowMemoStore) Len() int {
+	return s.count
+}
@@ -0,0 +1,190 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parser_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+// newResult creates a result for the memo store tests
+func newResult() *parsley.Result {
+	return &parsley.Result{LeftRecCtx: data.EmptyIntMap, CurtailingParsers: data.EmptyIntSet}
+}
+
+var _ = Describe("UnboundedMemoStore", func() {
+	It("should store all the results", func() {
+		s := parser.NewUnboundedMemoStore()
+		r1, r2 := newResult(), newResult()
+		s.Save(1, 1, r1)
+		s.Save(1, 2, r2)
+		s.Save(1, 2, r2)
+		Expect(s.Len()).To(Equal(2))
+
+		res, found := s.Get(1, 1)
+		Expect(found).To(BeTrue())
+		Expect(res).To(BeIdenticalTo(r1))
+		_, found = s.Get(2, 1)
+		Expect(found).To(BeFalse())
+		Expect(s.Has(1, 2)).To(BeTrue())
+		Expect(s.Has(2, 1)).To(BeFalse())
+	})
+})
+
+var _ = Describe("LRUMemoStore", func() {
+	It("should drop the least recently used result", func() {
+		s := parser.NewLRUMemoStore(2)
+		s.Save(1, 1, newResult())
+		s.Save(1, 2, newResult())
+		s.Get(1, 1)
+		s.Save(1, 3, newResult())
+		Expect(s.Len()).To(Equal(2))
+
+		_, found := s.Get(1, 1)
+		Expect(found).To(BeTrue())
+		_, found = s.Get(1, 2)
+		Expect(found).To(BeFalse())
+		_, found = s.Get(1, 3)
+		Expect(found).To(BeTrue())
+	})
+
+	It("should not mark a result as recently used when checking it", func() {
+		s := parser.NewLRUMemoStore(2)
+		s.Save(1, 1, newResult())
+		s.Save(1, 2, newResult())
+		Expect(s.Has(1, 1)).To(BeTrue())
+		Expect(s.Has(1, 3)).To(BeFalse())
+		s.Save(1, 3, newResult())
+
+		Expect(s.Has(1, 1)).To(BeFalse())
+		Expect(s.Has(1, 2)).To(BeTrue())
+		Expect(s.Has(1, 3)).To(BeTrue())
+	})
+
+	It("should update an existing result", func() {
+		s := parser.NewLRUMemoStore(2)
+		r := newResult()
+		s.Save(1, 1, newResult())
+		s.Save(1, 1, r)
+		Expect(s.Len()).To(Equal(1))
+		res, _ := s.Get(1, 1)
+		Expect(res).To(BeIdenticalTo(r))
+	})
+
+	It("should panic if the size is not positive", func() {
+		Expect(func() { parser.NewLRUMemoStore(0) }).To(Panic())
+	})
+})
+
+var _ = Describe("WindowMemoStore", func() {
+	It("should drop the results behind the committed position", func() {
+		s := parser.NewWindowMemoStore(0)
+		s.Save(1, 1, newResult())
+		s.Save(2, 1, newResult())
+		s.Save(1, 5, newResult())
+		s.Commit(3)
+		Expect(s.Len()).To(Equal(1))
+		_, found := s.Get(1, 1)
+		Expect(found).To(BeFalse())
+		_, found = s.Get(1, 5)
+		Expect(found).To(BeTrue())
+		Expect(s.Has(1, 1)).To(BeFalse())
+		Expect(s.Has(1, 5)).To(BeTrue())
+	})
+
+	It("should not store the results behind the committed position", func() {
+		s := parser.NewWindowMemoStore(0)
+		s.Commit(3)
+		s.Save(1, 2, newResult())
+		Expect(s.Len()).To(Equal(0))
+	})
+
+	It("should slide the window", func() {
+		s := parser.NewWindowMemoStore(2)
+		s.Save(1, 1, newResult())
+		s.Save(1, 2, newResult())
+		s.Save(1, 3, newResult())
+		Expect(s.Len()).To(Equal(3))
+		s.Save(1, 4, newResult())
+		Expect(s.Len()).To(Equal(3))
+		_, found := s.Get(1, 1)
+		Expect(found).To(BeFalse())
+		_, found = s.Get(1, 2)
+		Expect(found).To(BeTrue())
+	})
+
+	It("should handle large jumps", func() {
+		s := parser.NewWindowMemoStore(10)
+		s.Save(1, 1, newResult())
+		s.Save(1, 1000000, newResult())
+		Expect(s.Len()).To(Equal(1))
+	})
+})
+
+var _ = Describe("History with a memo store", func() {
+	// newSumParser returns with a left-recursive parser for sums, e.g. 1+2+3
+	newSumParser := func() parsley.Parser {
+		var sum parser.NamedFunc
+		value := ast.InterpreterFunc(func(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+			v1, _ := nodes[0].Value(ctx)
+			v2, _ := nodes[2].Value(ctx)
+			return v1.(int) + v2.(int), nil
+		})
+		sum = *combinator.Memoize(combinator.Any("sum",
+			combinator.Seq("SUM", "sum", &sum, terminal.Rune('+'), terminal.Integer()).Bind(value),
+			terminal.Integer(),
+		))
+		return combinator.Sentence(&sum)
+	}
+
+	DescribeTable("should parse correctly when the results are dropped",
+		func(store parser.MemoStore) {
+			h := parser.NewHistory().WithMemoStore(store)
+			r := text.NewReader(text.NewFile("", []byte("1+2+3+4+5")))
+			res, err := parsley.Evaluate(h, r, newSumParser(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(15))
+		},
+		Entry("unbounded", parser.NewUnboundedMemoStore()),
+		Entry("LRU", parser.NewLRUMemoStore(1)),
+		Entry("window", parser.NewWindowMemoStore(1)),
+	)
+
+	Describe("result limit", func() {
+		var p parsley.Parser
+
+		BeforeEach(func() {
+			p = combinator.Sentence(combinator.SepBy(combinator.Memoize(terminal.Integer()), terminal.Rune('+')))
+		})
+
+		It("should return an error if there are too many stored results", func() {
+			h := parser.NewHistoryWithLimits(parser.Limits{MaxResults: 2})
+			_, err := parsley.Parse(h, text.NewReader(text.NewFile("", []byte("1+2+3"))), p)
+			Expect(err).To(MatchError("exceeded the maximum number of memoized results (2)"))
+		})
+
+		It("should count the results dropped by the store", func() {
+			h := parser.NewHistoryWithLimits(parser.Limits{MaxResults: 3}).WithMemoStore(parser.NewLRUMemoStore(2))
+			_, err := parsley.Parse(h, text.NewReader(text.NewFile("", []byte("1+2+3+4+5"))), p)
+			Expect(err).To(MatchError("exceeded the maximum number of memoized results (3)"))
+		})
+
+		It("should not return an error within the limit", func() {
+			h := parser.NewHistoryWithLimits(parser.Limits{MaxResults: 3}).WithMemoStore(parser.NewLRUMemoStore(2))
+			_, err := parsley.Parse(h, text.NewReader(text.NewFile("", []byte("1+2+3"))), p)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -48,9 +48,34 @@ func (f Func) WithName(name interface{}) *NamedFunc {
 }
 
 // NamedFunc is a parser function with a custom name
+// It implements the parsley.Composite interface, the kind and the child parsers can be set with WithChildren.
 type NamedFunc struct {
-	name func() string
-	f    Func
+	name      func() string
+	f         Func
+	kind      parsley.ParserKind
+	children  []parsley.Parser
+	validator func() error
+	literal   string
+	word      bool
+}
+
+// WithName returns with a copy of the parser with the given name
+// If a function is passed then it will be called when Name() is called
+func (nf *NamedFunc) WithName(name interface{}) *NamedFunc {
+	res := nf.f.WithName(name)
+	res.kind = nf.kind
+	res.children = nf.children
+	res.validator = nf.validator
+	res.literal = nf.literal
+	res.word = nf.word
+	return res
+}
+
+// WithChildren sets the kind and the child parsers, so the parser's structure can be inspected
+func (nf *NamedFunc) WithChildren(kind parsley.ParserKind, children ...parsley.Parser) *NamedFunc {
+	nf.kind = kind
+	nf.children = children
+	return nf
 }
 
 // Parse parses the input using the function
@@ -58,7 +83,46 @@ func (nf *NamedFunc) Parse(h parsley.History, leftRecCtx data.IntMap, r parsley.
 	return nf.f(h, leftRecCtx, r, pos)
 }
 
+// WithValidator sets the function which checks the parser's configuration, see parsley.Validator
+func (nf *NamedFunc) WithValidator(validator func() error) *NamedFunc {
+	nf.validator = validator
+	return nf
+}
+
+// Validate checks the parser's configuration using the validator function if it was set
+func (nf *NamedFunc) Validate() error {
+	if nf.validator == nil {
+		return nil
+	}
+	return nf.validator()
+}
+
+// WithLiteral sets the fixed string the parser matches, see parsley.Literal
+func (nf *NamedFunc) WithLiteral(value string, word bool) *NamedFunc {
+	nf.literal = value
+	nf.word = word
+	return nf
+}
+
+// Literal returns with the fixed string the parser matches or an empty string if it was not set
+func (nf *NamedFunc) Literal() (string, bool) {
+	return nf.literal, nf.word
+}
+
 // Name returns with the parser name
 func (nf *NamedFunc) Name() string {
 	return nf.name()
 }
+
+// Kind returns with the parser kind, it's parsley.KindTerminal if it was not set
+func (nf *NamedFunc) Kind() parsley.ParserKind {
+	if nf.kind == "" {
+		return parsley.KindTerminal
+	}
+	return nf.kind
+}
+
+// Children returns with the child parsers
+func (nf *NamedFunc) Children() []parsley.Parser {
+	return nf.children
+}
@@ -85,4 +85,50 @@ var _ = Describe("Func", func() {
 			})
 		})
 	})
+
+	Describe("NamedFunc", func() {
+		var f parser.Func
+
+		BeforeEach(func() {
+			f = parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+				return nil, nil, data.EmptyIntSet
+			})
+		})
+
+		It("should be a terminal without children by default", func() {
+			p := f.WithName("p1")
+			Expect(p.Kind()).To(Equal(parsley.KindTerminal))
+			Expect(p.Children()).To(BeEmpty())
+		})
+
+		It("should expose the kind and the children if set", func() {
+			child := &parsleyfakes.FakeParser{}
+			p := f.WithName("p1").WithChildren(parsley.KindOptional, child)
+			Expect(p.Kind()).To(Equal(parsley.KindOptional))
+			Expect(p.Children()).To(Equal([]parsley.Parser{child}))
+		})
+
+		It("should be renamed by WithName and keep the structure", func() {
+			child := &parsleyfakes.FakeParser{}
+			p1 := f.WithName("p1").WithChildren(parsley.KindOptional, child)
+			p2 := p1.WithName("p2")
+			Expect(p1.Name()).To(Equal("p1"))
+			Expect(p2.Name()).To(Equal("p2"))
+			Expect(p2.Kind()).To(Equal(parsley.KindOptional))
+			Expect(p2.Children()).To(Equal([]parsley.Parser{child}))
+		})
+
+		It("should not have a literal by default", func() {
+			value, word := f.WithName("p1").Literal()
+			Expect(value).To(BeEmpty())
+			Expect(word).To(BeFalse())
+		})
+
+		It("should expose the literal if set and keep it when renamed", func() {
+			p := f.WithName("p1").WithLiteral("foo", true).WithName("p2")
+			value, word := p.Literal()
+			Expect(value).To(Equal("foo"))
+			Expect(word).To(BeTrue())
+		})
+	})
 })
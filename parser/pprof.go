@@ -0,0 +1,198 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"sort"
+)
+
+// WritePprof writes the collected statistics as a gzipped pprof profile
+// Every parser is a function in the profile and every sample is a parser call stack with the number of calls and
+// the self time. The samples have a "parser" label with the parser's name and a "kind" label with its kind.
+// The profile can be analysed with "go tool pprof".
+func (h *ProfileHistory) WritePprof(w io.Writer) error {
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	if _, err := zw.Write(h.pprofProfile()); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pprof profile.proto field numbers
+const (
+	pprofSampleType = 1
+	pprofSample     = 2
+	pprofLocation   = 4
+	pprofFunction   = 5
+	pprofStrings    = 6
+	pprofPeriodType = 11
+	pprofPeriod     = 12
+
+	pprofValueTypeType = 1
+	pprofValueTypeUnit = 2
+
+	pprofSampleLocationID = 1
+	pprofSampleValue      = 2
+	pprofSampleLabel      = 3
+
+	pprofLabelKey = 1
+	pprofLabelStr = 2
+
+	pprofLocationID   = 1
+	pprofLocationLine = 4
+
+	pprofLineFunctionID = 1
+
+	pprofFunctionID         = 1
+	pprofFunctionName       = 2
+	pprofFunctionSystemName = 3
+)
+
+// pprofProfile returns with the encoded profile.proto message
+func (h *ProfileHistory) pprofProfile() []byte {
+	strs := &pprofStringTable{index: map[string]int{}}
+	strs.get("")
+
+	ids := map[statsKey]uint64{}
+	var keys []statsKey
+	for key := range h.stats {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].name < keys[j].name || keys[i].name == keys[j].name && keys[i].kind < keys[j].kind
+	})
+
+	var p pprofBuffer
+	for _, valueType := range [][2]string{{"calls", "count"}, {"self_time", "nanoseconds"}} {
+		var vt pprofBuffer
+		vt.int(pprofValueTypeType, int64(strs.get(valueType[0])))
+		vt.int(pprofValueTypeUnit, int64(strs.get(valueType[1])))
+		p.message(pprofSampleType, vt.Bytes())
+	}
+
+	for i, key := range keys {
+		id := uint64(i + 1)
+		ids[key] = id
+		name := key.name
+		if name == "" {
+			name = "<" + string(key.kind) + ">"
+		}
+
+		var f pprofBuffer
+		f.int(pprofFunctionID, int64(id))
+		f.int(pprofFunctionName, int64(strs.get(name)))
+		f.int(pprofFunctionSystemName, int64(strs.get(string(key.kind))))
+		p.message(pprofFunction, f.Bytes())
+
+		var line pprofBuffer
+		line.int(pprofLineFunctionID, int64(id))
+		var loc pprofBuffer
+		loc.int(pprofLocationID, int64(id))
+		loc.message(pprofLocationLine, line.Bytes())
+		p.message(pprofLocation, loc.Bytes())
+	}
+
+	var sampleKeys []string
+	for key := range h.samples {
+		sampleKeys = append(sampleKeys, key)
+	}
+	sort.Strings(sampleKeys)
+	for _, key := range sampleKeys {
+		sample := h.samples[key]
+		locationIDs := make([]int64, len(sample.stack))
+		for i, key := range sample.stack {
+			locationIDs[i] = int64(ids[key])
+		}
+		var s pprofBuffer
+		s.packed(pprofSampleLocationID, locationIDs)
+		s.packed(pprofSampleValue, []int64{sample.calls, int64(sample.selfTime)})
+		for _, label := range [][2]string{{"parser", sample.stack[0].name}, {"kind", string(sample.stack[0].kind)}} {
+			var l pprofBuffer
+			l.int(pprofLabelKey, int64(strs.get(label[0])))
+			l.int(pprofLabelStr, int64(strs.get(label[1])))
+			s.message(pprofSampleLabel, l.Bytes())
+		}
+		p.message(pprofSample, s.Bytes())
+	}
+
+	var pt pprofBuffer
+	pt.int(pprofValueTypeType, int64(strs.get("calls")))
+	pt.int(pprofValueTypeUnit, int64(strs.get("count")))
+	p.message(pprofPeriodType, pt.Bytes())
+	p.int(pprofPeriod, 1)
+
+	for _, s := range strs.strings {
+		p.bytes(pprofStrings, []byte(s))
+	}
+	return p.Bytes()
+}
+
+// pprofStringTable is the string table of the profile, the first string must be empty
+type pprofStringTable struct {
+	strings []string
+	index   map[string]int
+}
+
+func (t *pprofStringTable) get(s string) int {
+	if i, ok := t.index[s]; ok {
+		return i
+	}
+	t.index[s] = len(t.strings)
+	t.strings = append(t.strings, s)
+	return len(t.strings) - 1
+}
+
+// pprofBuffer encodes protocol buffer messages
+type pprofBuffer struct {
+	bytes.Buffer
+}
+
+func (b *pprofBuffer) varint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	b.Write(buf[:binary.PutUvarint(buf[:], v)])
+}
+
+func (b *pprofBuffer) key(field int, wireType int) {
+	b.varint(uint64(field<<3 | wireType))
+}
+
+// int writes a varint field, the zero values are omitted
+func (b *pprofBuffer) int(field int, v int64) {
+	if v == 0 {
+		return
+	}
+	b.key(field, 0)
+	b.varint(uint64(v))
+}
+
+// bytes writes a length-delimited field
+func (b *pprofBuffer) bytes(field int, data []byte) {
+	b.key(field, 2)
+	b.varint(uint64(len(data)))
+	b.Write(data)
+}
+
+func (b *pprofBuffer) message(field int, data []byte) {
+	b.bytes(field, data)
+}
+
+// packed writes a packed repeated varint field
+func (b *pprofBuffer) packed(field int, values []int64) {
+	var data pprofBuffer
+	for _, v := range values {
+		data.varint(uint64(v))
+	}
+	b.bytes(field, data.Bytes())
+}
@@ -0,0 +1,263 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// ProfileHistory is a history which collects statistics about the parser calls
+// It wraps an other history which handles the memoization and the limits. The parser calls are reported by the
+// combinators through parsley.Call. The statistics are collected for every parser name and kind.
+type ProfileHistory struct {
+	parsley.History
+	stats   map[statsKey]*ParserStats
+	samples map[string]*profileSample
+	stack   []*profileFrame
+	active  map[statsKey]int
+	now     func() time.Time
+}
+
+// NewProfileHistory creates a profiling history which wraps the given history
+func NewProfileHistory(h parsley.History) *ProfileHistory {
+	return &ProfileHistory{
+		History: h,
+		stats:   map[statsKey]*ParserStats{},
+		samples: map[string]*profileSample{},
+		active:  map[statsKey]int{},
+		now:     time.Now,
+	}
+}
+
+type statsKey struct {
+	name string
+	kind parsley.ParserKind
+}
+
+// profileFrame is a parser call in progress
+type profileFrame struct {
+	key       statsKey
+	stats     *ParserStats
+	sample    string
+	start     time.Time
+	childTime time.Duration
+}
+
+// profileSample contains the statistics of a call stack
+type profileSample struct {
+	stack    []statsKey
+	calls    int64
+	selfTime time.Duration
+}
+
+// TraceCall registers the start of a parser call
+func (h *ProfileHistory) TraceCall(p parsley.Parser, pos parsley.Pos) {
+	key := statsKey{name: p.Name(), kind: parsley.KindOf(p)}
+	stats, ok := h.stats[key]
+	if !ok {
+		stats = &ParserStats{Name: key.name, Kind: key.kind}
+		h.stats[key] = stats
+	}
+	stats.Calls++
+
+	sample := fmt.Sprintf("%s\x00%s", key.kind, key.name)
+	if len(h.stack) > 0 {
+		sample = h.stack[len(h.stack)-1].sample + "\x00" + sample
+	}
+	h.active[key]++
+	h.stack = append(h.stack, &profileFrame{
+		key:    key,
+		stats:  stats,
+		sample: sample,
+		start:  h.now(),
+	})
+}
+
+// TraceResult registers the result of the last started parser call
+func (h *ProfileHistory) TraceResult(p parsley.Parser, pos parsley.Pos, node parsley.Node, err parsley.Error) {
+	if len(h.stack) == 0 {
+		return
+	}
+	frame := h.stack[len(h.stack)-1]
+	h.stack = h.stack[:len(h.stack)-1]
+
+	elapsed := h.now().Sub(frame.start)
+	if len(h.stack) > 0 {
+		h.stack[len(h.stack)-1].childTime += elapsed
+	}
+	// the recursive calls of the same parser are only counted once in the total time
+	h.active[frame.key]--
+	if h.active[frame.key] == 0 {
+		frame.stats.TotalTime += elapsed
+	}
+	frame.stats.SelfTime += elapsed - frame.childTime
+	if node != nil {
+		frame.stats.Matches++
+		frame.stats.MatchedLength += int(nodeEndPos(node, pos) - pos)
+	}
+
+	sample, ok := h.samples[frame.sample]
+	if !ok {
+		stack := make([]statsKey, 0, len(h.stack)+1)
+		stack = append(stack, frame.key)
+		for i := len(h.stack) - 1; i >= 0; i-- {
+			stack = append(stack, h.stack[i].key)
+		}
+		sample = &profileSample{stack: stack}
+		h.samples[frame.sample] = sample
+	}
+	sample.calls++
+	sample.selfTime += elapsed - frame.childTime
+}
+
+// Unwrap returns with the wrapped history
+func (h *ProfileHistory) Unwrap() parsley.History {
+	return h.History
+}
+
+// GetResult returns with a previously saved result and counts the memo hits and misses of the current parser
+func (h *ProfileHistory) GetResult(parserIndex int, pos parsley.Pos, leftRecCtx data.IntMap) (*parsley.Result, bool) {
+	result, found := h.History.GetResult(parserIndex, pos, leftRecCtx)
+	if len(h.stack) > 0 {
+		if stats := h.stack[len(h.stack)-1].stats; found {
+			stats.MemoHits++
+		} else {
+			stats.MemoMisses++
+		}
+	}
+	return result, found
+}
+
+// Stats returns with the collected statistics ordered by the total time
+func (h *ProfileHistory) Stats() Stats {
+	res := make(Stats, 0, len(h.stats))
+	for _, stats := range h.stats {
+		res = append(res, stats)
+	}
+	return res.SortBy(StatsTotalTime)
+}
+
+// ParserStats contains the statistics of the parsers with the same name and kind
+type ParserStats struct {
+	// Name is the parser name
+	Name string
+	// Kind is the parser kind, see parsley.KindOf
+	Kind parsley.ParserKind
+	// Calls is the number of parser calls
+	Calls int
+	// Matches is the number of calls which returned with a result
+	Matches int
+	// MemoHits is the number of calls where the result was found in the memoization cache
+	MemoHits int
+	// MemoMisses is the number of calls where the result was not found in the memoization cache
+	MemoMisses int
+	// TotalTime is the time spent in the parser including the child parsers
+	TotalTime time.Duration
+	// SelfTime is the time spent in the parser without the child parsers
+	SelfTime time.Duration
+	// MatchedLength is the total length of the matched input
+	MatchedLength int
+}
+
+// MemoHitRate returns with the ratio of the memo hits, it's zero if the parser is not memoized
+func (s *ParserStats) MemoHitRate() float64 {
+	if s.MemoHits+s.MemoMisses == 0 {
+		return 0
+	}
+	return float64(s.MemoHits) / float64(s.MemoHits+s.MemoMisses)
+}
+
+// StatsColumn is a column of the statistics table
+type StatsColumn string
+
+// Statistics table columns
+const (
+	StatsName          StatsColumn = "name"
+	StatsKind          StatsColumn = "kind"
+	StatsCalls         StatsColumn = "calls"
+	StatsMatches       StatsColumn = "matches"
+	StatsMemoHits      StatsColumn = "memo hits"
+	StatsMemoMisses    StatsColumn = "memo misses"
+	StatsTotalTime     StatsColumn = "total time"
+	StatsSelfTime      StatsColumn = "self time"
+	StatsMatchedLength StatsColumn = "matched length"
+)
+
+// Stats is a list of parser statistics
+type Stats []*ParserStats
+
+// SortBy returns with the statistics sorted by the given column
+// The name and the kind are sorted in ascending order, all the other columns are sorted in descending order.
+func (s Stats) SortBy(column StatsColumn) Stats {
+	res := make(Stats, len(s))
+	copy(res, s)
+	value := func(s *ParserStats) int64 {
+		switch column {
+		case StatsCalls:
+			return int64(s.Calls)
+		case StatsMatches:
+			return int64(s.Matches)
+		case StatsMemoHits:
+			return int64(s.MemoHits)
+		case StatsMemoMisses:
+			return int64(s.MemoMisses)
+		case StatsTotalTime:
+			return int64(s.TotalTime)
+		case StatsSelfTime:
+			return int64(s.SelfTime)
+		case StatsMatchedLength:
+			return int64(s.MatchedLength)
+		default:
+			panic(fmt.Sprintf("unknown statistics column: %s", column))
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		switch column {
+		case StatsName:
+			return a.Name < b.Name || a.Name == b.Name && a.Kind < b.Kind
+		case StatsKind:
+			return a.Kind < b.Kind || a.Kind == b.Kind && a.Name < b.Name
+		}
+		if va, vb := value(a), value(b); va != vb {
+			return va > vb
+		}
+		return a.Name < b.Name || a.Name == b.Name && a.Kind < b.Kind
+	})
+	return res
+}
+
+// WriteTable writes the statistics as a text table
+func (s Stats) WriteTable(w io.Writer) error {
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	columns := []StatsColumn{
+		StatsCalls, StatsMatches, StatsMemoHits, StatsMemoMisses, StatsTotalTime, StatsSelfTime, StatsMatchedLength,
+	}
+	for _, c := range columns {
+		fmt.Fprintf(tw, "%s\t", c)
+	}
+	fmt.Fprintf(tw, "  %-9s %s\n", StatsKind, StatsName)
+	for _, stats := range s {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%s\t%s\t%d\t  %-9s %s\n",
+			stats.Calls, stats.Matches, stats.MemoHits, stats.MemoMisses, stats.TotalTime, stats.SelfTime,
+			stats.MatchedLength, stats.Kind, strings.Replace(stats.Name, "\n", " ", -1))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
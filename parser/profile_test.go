@@ -0,0 +1,138 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parser_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+var _ = Describe("ProfileHistory", func() {
+	var (
+		h *parser.ProfileHistory
+	)
+
+	// findStats returns with the statistics of the parser with the given name and kind
+	findStats := func(name string, kind parsley.ParserKind) *parser.ParserStats {
+		for _, stats := range h.Stats() {
+			if stats.Name == name && stats.Kind == kind {
+				return stats
+			}
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		h = parser.NewProfileHistory(parser.NewHistory())
+		f := text.NewFile("", []byte("a"))
+		_, err := parsley.Parse(h, text.NewReader(f), newTraceParser())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should count the calls and the matches", func() {
+		stats := findStats("ab", parsley.KindSeq)
+		Expect(stats).ToNot(BeNil())
+		Expect(stats.Calls).To(Equal(1))
+		Expect(stats.Matches).To(Equal(0))
+
+		stats = findStats(`"a"`, parsley.KindTerminal)
+		Expect(stats.Calls).To(Equal(1))
+		Expect(stats.Matches).To(Equal(1))
+		Expect(stats.MatchedLength).To(Equal(1))
+	})
+
+	It("should count the memo hits and misses", func() {
+		stats := findStats(`"a"`, parsley.KindMemoize)
+		Expect(stats.Calls).To(Equal(2))
+		Expect(stats.MemoHits).To(Equal(1))
+		Expect(stats.MemoMisses).To(Equal(1))
+		Expect(stats.MemoHitRate()).To(Equal(0.5))
+	})
+
+	It("should measure the time", func() {
+		root := findStats("value", parsley.KindSeq)
+		Expect(root.TotalTime).To(BeNumerically(">=", root.SelfTime))
+		Expect(h.Stats()[0]).To(Equal(root))
+	})
+
+	It("should measure the matched length of an ambiguous result", func() {
+		h = parser.NewProfileHistory(parser.NewHistory())
+		f := text.NewFile("", []byte("abc"))
+		_, err := parsley.Parse(h, text.NewReader(f), newAmbiguousParser())
+		Expect(err).ToNot(HaveOccurred())
+		stats := findStats("a or ab", parsley.KindAny)
+		Expect(stats.Matches).To(Equal(1))
+		Expect(stats.MatchedLength).To(Equal(2))
+	})
+
+	It("should profile the calls when it's stacked with a trace history", func() {
+		h = parser.NewProfileHistory(parser.NewHistory())
+		th := parser.NewTraceHistory(h)
+		f := text.NewFile("", []byte("a"))
+		_, err := parsley.Parse(th, text.NewReader(f), newTraceParser())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findStats("ab", parsley.KindSeq).Calls).To(Equal(1))
+		Expect(findStats(`"a"`, parsley.KindMemoize).MemoHits).To(Equal(1))
+		Expect(th.Trace()).ToNot(BeEmpty())
+		Expect(th.Trace()[0].Name).To(Equal("value"))
+
+		th = parser.NewTraceHistory(parser.NewHistory())
+		h = parser.NewProfileHistory(th)
+		_, err = parsley.Parse(h, text.NewReader(f), newTraceParser())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findStats("ab", parsley.KindSeq).Calls).To(Equal(1))
+		Expect(th.Trace()).ToNot(BeEmpty())
+		Expect(th.Trace()[0].Name).To(Equal("value"))
+	})
+
+	It("should delegate to the wrapped history", func() {
+		Expect(h.CallCount()).To(BeNumerically(">", 0))
+	})
+
+	Describe("Stats", func() {
+		It("should sort by the given column", func() {
+			stats := h.Stats().SortBy(parser.StatsCalls)
+			Expect(stats[0].Name).To(Equal(`"a"`))
+			Expect(stats[0].Kind).To(Equal(parsley.KindMemoize))
+
+			stats = h.Stats().SortBy(parser.StatsName)
+			Expect(stats[0].Name).To(Equal(`"a"`))
+			Expect(stats[len(stats)-1].Name).To(Equal("value"))
+		})
+
+		It("should write a table", func() {
+			var b bytes.Buffer
+			Expect(h.Stats().SortBy(parser.StatsName).WriteTable(&b)).To(Succeed())
+			lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+			Expect(lines).To(HaveLen(8))
+			Expect(strings.TrimSpace(lines[0])).To(HavePrefix("calls  matches  memo hits  memo misses"))
+			Expect(lines[0]).To(HaveSuffix("matched length  kind      name"))
+			Expect(strings.Fields(lines[1])[:4]).To(Equal([]string{"2", "2", "1", "1"}))
+			Expect(lines[1]).To(HaveSuffix(`memoize   "a"`))
+		})
+	})
+
+	It("should write a pprof profile", func() {
+		var b bytes.Buffer
+		Expect(h.WritePprof(&b)).To(Succeed())
+		zr, err := gzip.NewReader(&b)
+		Expect(err).ToNot(HaveOccurred())
+		data, err := ioutil.ReadAll(zr)
+		Expect(err).ToNot(HaveOccurred())
+		for _, s := range []string{"calls", "self_time", "nanoseconds", "parser", "value", "ab", `"a"`, "memoize"} {
+			Expect(string(data)).To(ContainSubstring(s))
+		}
+	})
+})
@@ -18,10 +18,11 @@ import (
 )
 
 // Nil always matches and returns with an nil node result
-func Nil() Func {
+// It has an empty name.
+func Nil() *NamedFunc {
 	return Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
 		return ast.NilNode(pos), nil, data.EmptyIntSet
-	})
+	}).WithName("").WithChildren(parsley.KindEmpty)
 }
 
 // End matches the end of the input
@@ -33,6 +33,10 @@ var _ = Describe("Nil", func() {
 		Expect(p.Name()).To(BeEmpty())
 	})
 
+	It("should have the empty kind", func() {
+		Expect(parsley.KindOf(p)).To(Equal(parsley.KindEmpty))
+	})
+
 	It("should return with an empty node", func() {
 		res, err, curtailingParsers := p.Parse(h, data.EmptyIntMap, r, 1)
 		Expect(curtailingParsers).To(Equal(data.EmptyIntSet))
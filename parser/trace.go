@@ -0,0 +1,232 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// TraceHistory is a history which records every parser call in a call tree
+// It wraps an other history which handles the memoization and the limits. The parser calls are reported by the
+// combinators through parsley.Call.
+type TraceHistory struct {
+	parsley.History
+	calls Trace
+	stack []*TraceCall
+}
+
+// NewTraceHistory creates a tracing history which wraps the given history
+func NewTraceHistory(h parsley.History) *TraceHistory {
+	return &TraceHistory{
+		History: h,
+	}
+}
+
+// TraceCall registers the start of a parser call
+func (h *TraceHistory) TraceCall(p parsley.Parser, pos parsley.Pos) {
+	call := &TraceCall{
+		Name: p.Name(),
+		Kind: parsley.KindOf(p),
+		Pos:  pos,
+	}
+	if len(h.stack) > 0 {
+		parent := h.stack[len(h.stack)-1]
+		parent.Calls = append(parent.Calls, call)
+	} else {
+		h.calls = append(h.calls, call)
+	}
+	h.stack = append(h.stack, call)
+}
+
+// TraceResult registers the result of the last started parser call
+func (h *TraceHistory) TraceResult(p parsley.Parser, pos parsley.Pos, node parsley.Node, err parsley.Error) {
+	if len(h.stack) == 0 {
+		return
+	}
+	call := h.stack[len(h.stack)-1]
+	h.stack = h.stack[:len(h.stack)-1]
+	call.Matched = node != nil
+	call.EndPos = pos
+	if node != nil {
+		call.EndPos = nodeEndPos(node, pos)
+	}
+	call.Err = err
+}
+
+// Unwrap returns with the wrapped history
+func (h *TraceHistory) Unwrap() parsley.History {
+	return h.History
+}
+
+// GetResult returns with a previously saved result, the current call is marked as a memo hit if a result is found
+func (h *TraceHistory) GetResult(parserIndex int, pos parsley.Pos, leftRecCtx data.IntMap) (*parsley.Result, bool) {
+	result, found := h.History.GetResult(parserIndex, pos, leftRecCtx)
+	if found && len(h.stack) > 0 {
+		h.stack[len(h.stack)-1].MemoHit = true
+	}
+	return result, found
+}
+
+// Trace returns with the recorded top-level parser calls
+func (h *TraceHistory) Trace() Trace {
+	return h.calls
+}
+
+// TraceCall is a recorded parser call
+type TraceCall struct {
+	// Name is the parser name
+	Name string
+	// Kind is the parser kind, see parsley.KindOf
+	Kind parsley.ParserKind
+	// Pos is the position where the parser was called
+	Pos parsley.Pos
+	// EndPos is the reader position after the match or the start position if the parser didn't match
+	EndPos parsley.Pos
+	// Matched is true if the parser returned with a result
+	Matched bool
+	// MemoHit is true if the result was returned from the memoization cache
+	MemoHit bool
+	// Err is the error returned by the parser
+	Err parsley.Error
+	// Calls are the parser calls made by the parser
+	Calls Trace
+}
+
+// end returns with the furthest position the call has reached
+func (c *TraceCall) end() parsley.Pos {
+	if c.Err != nil && c.Err.Pos() > c.EndPos {
+		return c.Err.Pos()
+	}
+	return c.EndPos
+}
+
+// Trace is a list of parser calls
+type Trace []*TraceCall
+
+// Around returns with the calls which touch the input between pos-distance and pos+distance
+// The parent calls of the matching calls are kept, so the result is still a call tree.
+func (t Trace) Around(pos parsley.Pos, distance int) Trace {
+	from, to := pos-parsley.Pos(distance), pos+parsley.Pos(distance)
+	var res Trace
+	for _, call := range t {
+		calls := call.Calls.Around(pos, distance)
+		if len(calls) > 0 || call.Pos <= to && call.end() >= from {
+			filtered := *call
+			filtered.Calls = calls
+			res = append(res, &filtered)
+		}
+	}
+	return res
+}
+
+// WriteTree writes the calls as an indented tree, one call in every line
+// If a file set is given then the positions are resolved, otherwise the raw positions are written.
+func (t Trace) WriteTree(w io.Writer, fs *parsley.FileSet) error {
+	var b bytes.Buffer
+	t.writeTree(&b, fs, 0)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func (t Trace) writeTree(b *bytes.Buffer, fs *parsley.FileSet, depth int) {
+	for _, call := range t {
+		b.WriteString(strings.Repeat("  ", depth))
+		fmt.Fprintf(b, "%s (%s) %s", call.Name, call.Kind, tracePosition(fs, call.Pos))
+		if call.Matched {
+			fmt.Fprintf(b, "-%s", tracePosition(fs, call.EndPos))
+		} else {
+			b.WriteString(" failed")
+		}
+		if call.MemoHit {
+			b.WriteString(" memo hit")
+		}
+		if call.Err != nil {
+			fmt.Fprintf(b, ": %s", call.Err.Error())
+		}
+		b.WriteString("\n")
+		call.Calls.writeTree(b, fs, depth+1)
+	}
+}
+
+// WriteJSON writes the calls as a JSON array
+// If a file set is given then the resolved positions are also written.
+func (t Trace) WriteJSON(w io.Writer, fs *parsley.FileSet) error {
+	data, err := json.Marshal(t.jsonCalls(fs))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// jsonTraceCall is the JSON representation of a parser call
+type jsonTraceCall struct {
+	Name        string           `json:"name"`
+	Kind        string           `json:"kind"`
+	Pos         int              `json:"pos"`
+	Position    string           `json:"position,omitempty"`
+	EndPos      int              `json:"endPos"`
+	EndPosition string           `json:"endPosition,omitempty"`
+	Matched     bool             `json:"matched"`
+	MemoHit     bool             `json:"memoHit,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	Calls       []*jsonTraceCall `json:"calls,omitempty"`
+}
+
+func (t Trace) jsonCalls(fs *parsley.FileSet) []*jsonTraceCall {
+	res := make([]*jsonTraceCall, len(t))
+	for i, call := range t {
+		res[i] = &jsonTraceCall{
+			Name:    call.Name,
+			Kind:    string(call.Kind),
+			Pos:     int(call.Pos),
+			EndPos:  int(call.EndPos),
+			Matched: call.Matched,
+			MemoHit: call.MemoHit,
+			Calls:   call.Calls.jsonCalls(fs),
+		}
+		if fs != nil {
+			res[i].Position = fs.Position(call.Pos).String()
+			res[i].EndPosition = fs.Position(call.EndPos).String()
+		}
+		if call.Err != nil {
+			res[i].Error = call.Err.Error()
+		}
+	}
+	return res
+}
+
+// nodeEndPos returns with the reader position after the node
+// An ambiguous result (ast.NodeList) has no reader position, so the furthest reader position of its nodes is returned.
+func nodeEndPos(node parsley.Node, pos parsley.Pos) parsley.Pos {
+	nl, ok := node.(ast.NodeList)
+	if !ok {
+		return node.ReaderPos()
+	}
+	end := pos
+	for _, n := range nl {
+		if p := nodeEndPos(n, pos); p > end {
+			end = p
+		}
+	}
+	return end
+}
+
+// tracePosition returns with the resolved position if there is a file set, otherwise with the raw position
+func tracePosition(fs *parsley.FileSet, pos parsley.Pos) string {
+	if fs == nil {
+		return fmt.Sprintf("%d", pos)
+	}
+	return fs.Position(pos).String()
+}
@@ -0,0 +1,140 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parser_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+// newTraceParser returns with a parser which matches "ab" or "a", the "a" result is memoized
+func newTraceParser() parsley.Parser {
+	a := combinator.Memoize(terminal.Rune('a'))
+	return combinator.Sentence(combinator.Choice("value",
+		combinator.Seq("AB", "ab", a, terminal.Rune('b')),
+		a,
+	))
+}
+
+// newAmbiguousParser returns with a parser where both "a" and "ab" matches at the beginning of "abc"
+func newAmbiguousParser() parsley.Parser {
+	return combinator.Seq("S", "s",
+		combinator.Any("a or ab", terminal.Rune('a'), terminal.Substring("AB", "ab", "ab")),
+		terminal.Rune('c'),
+	)
+}
+
+// Let's trace the parser calls.
+func ExampleTraceHistory() {
+	f := text.NewFile("", []byte("a"))
+	h := parser.NewTraceHistory(parser.NewHistory())
+	parsley.Parse(h, text.NewReader(f), newTraceParser())
+	h.Trace().WriteTree(os.Stdout, parsley.NewFileSet(f))
+	// Output:
+	// value (seq) 1:1-1:2: was expecting "b"
+	//   value (choice) 1:1-1:2: was expecting "b"
+	//     ab (seq) 1:1 failed: was expecting "b"
+	//       "a" (memoize) 1:1-1:2
+	//         "a" (terminal) 1:1-1:2
+	//       "b" (terminal) 1:2 failed
+	//     "a" (memoize) 1:1-1:2 memo hit
+	//   the end of input (terminal) 1:2-1:2
+}
+
+var _ = Describe("TraceHistory", func() {
+	var (
+		h *parser.TraceHistory
+		f *text.File
+	)
+
+	BeforeEach(func() {
+		h = parser.NewTraceHistory(parser.NewHistory())
+		f = text.NewFile("", []byte("ab"))
+		_, err := parsley.Parse(h, text.NewReader(f), newTraceParser())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should record the call tree", func() {
+		trace := h.Trace()
+		Expect(trace).To(HaveLen(1))
+		Expect(trace[0].Kind).To(Equal(parsley.KindSeq))
+		Expect(trace[0].Matched).To(BeTrue())
+		Expect(trace[0].Pos).To(Equal(f.Pos(0)))
+		Expect(trace[0].EndPos).To(Equal(f.Pos(2)))
+
+		value := trace[0].Calls[0]
+		Expect(value.Name).To(Equal("value"))
+		Expect(value.Calls).To(HaveLen(1))
+		Expect(value.Calls[0].Name).To(Equal("ab"))
+		Expect(value.Calls[0].Calls).To(HaveLen(2))
+		Expect(value.Calls[0].Calls[0].MemoHit).To(BeFalse())
+	})
+
+	It("should use the furthest reader position for an ambiguous result", func() {
+		h := parser.NewTraceHistory(parser.NewHistory())
+		f := text.NewFile("", []byte("abc"))
+		_, err := parsley.Parse(h, text.NewReader(f), newAmbiguousParser())
+		Expect(err).ToNot(HaveOccurred())
+		any := h.Trace()[0].Calls[0]
+		Expect(any.Name).To(Equal("a or ab"))
+		Expect(any.Matched).To(BeTrue())
+		Expect(any.EndPos).To(Equal(f.Pos(2)))
+	})
+
+	It("should delegate to the wrapped history", func() {
+		Expect(h.CallCount()).To(BeNumerically(">", 0))
+	})
+
+	It("should write the calls as JSON", func() {
+		var b bytes.Buffer
+		Expect(h.Trace().WriteJSON(&b, parsley.NewFileSet(f))).To(Succeed())
+		var calls []map[string]interface{}
+		Expect(json.Unmarshal(b.Bytes(), &calls)).To(Succeed())
+		Expect(calls).To(HaveLen(1))
+		Expect(calls[0]).To(HaveKeyWithValue("kind", "seq"))
+		Expect(calls[0]).To(HaveKeyWithValue("position", "1:1"))
+		Expect(calls[0]).To(HaveKeyWithValue("endPosition", "1:3"))
+		Expect(calls[0]).To(HaveKeyWithValue("matched", true))
+		Expect(calls[0]["calls"]).To(HaveLen(2))
+	})
+
+	It("should filter the calls around a position", func() {
+		var b bytes.Buffer
+		Expect(h.Trace().Around(f.Pos(2), 0).WriteTree(&b, nil)).To(Succeed())
+		Expect(b.String()).To(Equal(`value (seq) 1-3
+  value (choice) 1-3
+    ab (seq) 1-3
+      "b" (terminal) 2-3
+  the end of input (terminal) 3-3
+`))
+	})
+
+	It("should not modify the original calls when filtering", func() {
+		h.Trace().Around(f.Pos(2), 0)
+		Expect(h.Trace()[0].Calls[0].Calls[0].Calls).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("parsley.ParseContext with a TraceHistory", func() {
+	It("should trace the parser calls", func() {
+		h := parser.NewTraceHistory(parser.NewHistory())
+		f := text.NewFile("", []byte("a"))
+		_, err := parsley.ParseContext(context.Background(), h, text.NewReader(f), newTraceParser())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(h.Trace()).To(HaveLen(1))
+	})
+})
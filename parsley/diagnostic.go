@@ -0,0 +1,205 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parsley
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[1;31m"
+	ansiBlue  = "\x1b[1;34m"
+)
+
+// DefaultTabWidth is the tab width used for aligning the source excerpts if not set otherwise
+const DefaultTabWidth = 4
+
+// DiagnosticOptions contains the options for rendering diagnostics
+type DiagnosticOptions struct {
+	// Color enables the ANSI colour codes in the output
+	Color bool
+	// TabWidth is the number of columns a tab character is aligned to, DefaultTabWidth is used if zero
+	TabWidth int
+	// ContextLines is the number of source lines displayed before and after the error
+	ContextLines int
+}
+
+// Diagnostics renders the given errors with source excerpts and returns with the result
+func (fs *FileSet) Diagnostics(opts DiagnosticOptions, errs ...Error) string {
+	buf := &bytes.Buffer{}
+	fs.WriteDiagnostics(buf, opts, errs...) // nolint
+	return buf.String()
+}
+
+// WriteDiagnostics renders the given errors with source excerpts and writes the result to w
+// The error's range is marked with carets under the source code. If the error's file doesn't implement the
+// SourceFile interface then only the error message and the position is written.
+func (fs *FileSet) WriteDiagnostics(w io.Writer, opts DiagnosticOptions, errs ...Error) error {
+	if opts.TabWidth <= 0 {
+		opts.TabWidth = DefaultTabWidth
+	}
+	for i, e := range errs {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		d := &diagnostic{opts: opts}
+		fs.renderDiagnostic(d, e)
+		if _, err := io.WriteString(w, d.buf.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fs *FileSet) renderDiagnostic(d *diagnostic, e Error) {
+	d.write(ansiRed, "error")
+	d.write(ansiBold, ": "+e.Error())
+	d.buf.WriteString("\n")
+
+	pos := e.Pos()
+	position := fs.Position(pos)
+	if position == NilPosition {
+		return
+	}
+
+	f, offset := fs.file(pos)
+	src, ok := f.(SourceFile)
+	if !ok {
+		d.write(ansiBlue, "-->")
+		d.buf.WriteString(" " + position.String() + "\n")
+		return
+	}
+
+	endOffset := offset + 1
+	if span := ErrorSpan(e); span.Len() > 0 {
+		endOffset = offset + span.Len()
+		if endOffset > f.Len() {
+			endOffset = f.Len()
+		}
+		if endOffset <= offset {
+			endOffset = offset + 1
+		}
+	}
+
+	line := src.LineNumber(offset)
+	endLine := src.LineNumber(endOffset - 1)
+	if endLine < line {
+		endLine = line
+	}
+	first := line - d.opts.ContextLines
+	if first < 1 {
+		first = 1
+	}
+	last := endLine + d.opts.ContextLines
+	if last > src.LineCount() {
+		last = src.LineCount()
+	}
+
+	gutter := strings.Repeat(" ", len(strconv.Itoa(last)))
+	d.buf.WriteString(gutter)
+	d.write(ansiBlue, "-->")
+	d.buf.WriteString(" " + position.String() + "\n")
+	d.buf.WriteString(gutter + " ")
+	d.write(ansiBlue, "|")
+	d.buf.WriteString("\n")
+
+	for n := first; n <= last; n++ {
+		lineOffset, content := src.Line(n)
+		content = bytes.TrimRight(content, "\r\n")
+
+		d.write(ansiBlue, fmt.Sprintf("%*d |", len(gutter), n))
+		if len(content) > 0 {
+			d.buf.WriteString(" " + d.expandTabs(content))
+		}
+		d.buf.WriteString("\n")
+
+		if n < line || n > endLine {
+			continue
+		}
+
+		start, end := 0, len(content)+1
+		if n == line {
+			start = offset - lineOffset
+		}
+		if n == endLine {
+			end = endOffset - lineOffset
+		}
+		startCol := d.width(content, start)
+		endCol := d.width(content, end)
+		if endCol <= startCol {
+			endCol = startCol + 1
+		}
+
+		d.buf.WriteString(gutter + " ")
+		d.write(ansiBlue, "|")
+		d.buf.WriteString(" " + strings.Repeat(" ", startCol))
+		d.write(ansiRed, strings.Repeat("^", endCol-startCol))
+		d.buf.WriteString("\n")
+	}
+}
+
+type diagnostic struct {
+	opts DiagnosticOptions
+	buf  bytes.Buffer
+}
+
+func (d *diagnostic) write(color string, s string) {
+	if d.opts.Color {
+		d.buf.WriteString(color + s + ansiReset)
+	} else {
+		d.buf.WriteString(s)
+	}
+}
+
+// width returns with the visual width of the first n bytes of the line
+// If n is greater than the line length then every extra byte counts as one column.
+func (d *diagnostic) width(line []byte, n int) int {
+	extra := 0
+	if n > len(line) {
+		extra = n - len(line)
+		n = len(line)
+	}
+	col := 0
+	for i := 0; i < n; {
+		r, size := utf8.DecodeRune(line[i:])
+		if r == '\t' {
+			col += d.opts.TabWidth - col%d.opts.TabWidth
+		} else {
+			col++
+		}
+		i += size
+	}
+	return col + extra
+}
+
+// expandTabs replaces the tab characters with spaces using the configured tab width
+func (d *diagnostic) expandTabs(line []byte) string {
+	if bytes.IndexByte(line, '\t') == -1 {
+		return string(line)
+	}
+	res := &bytes.Buffer{}
+	col := 0
+	for _, r := range string(line) {
+		if r == '\t' {
+			spaces := d.opts.TabWidth - col%d.opts.TabWidth
+			res.WriteString(strings.Repeat(" ", spaces))
+			col += spaces
+		} else {
+			res.WriteRune(r)
+			col++
+		}
+	}
+	return res.String()
+}
@@ -0,0 +1,236 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parsley_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/parsley/parsleyfakes"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+func ExampleFileSet_Diagnostics() {
+	f := text.NewFile("example.file", []byte("x = 1\ny = \"a\" +\nz = 3\n"))
+	fs := parsley.NewFileSet(f)
+	err := parsley.NewRangeError(f.Pos(10), f.Pos(13), errors.New("was expecting integer value"))
+	fmt.Print(fs.Diagnostics(parsley.DiagnosticOptions{ContextLines: 1}, err))
+	// Output:
+	// error: was expecting integer value
+	//  --> example.file:2:5
+	//   |
+	// 1 | x = 1
+	// 2 | y = "a" +
+	//   |     ^^^
+	// 3 | z = 3
+}
+
+var _ = Describe("Diagnostics", func() {
+	var (
+		fs   *parsley.FileSet
+		f    *text.File
+		opts parsley.DiagnosticOptions
+		errs []parsley.Error
+		res  string
+		data []byte
+	)
+
+	BeforeEach(func() {
+		data = []byte("first\nsecond line\n\tthird\nfourth")
+		opts = parsley.DiagnosticOptions{}
+		errs = nil
+	})
+
+	JustBeforeEach(func() {
+		f = text.NewFile("testfile", data)
+		fs = parsley.NewFileSet(f)
+		res = fs.Diagnostics(opts, errs...)
+	})
+
+	Context("when the error has a single position", func() {
+		BeforeEach(func() {
+			errs = []parsley.Error{parsley.NewErrorf(parsley.Pos(14), "some error")}
+		})
+
+		It("should mark the position with a caret", func() {
+			Expect(res).To(Equal("" +
+				"error: some error\n" +
+				" --> testfile:2:8\n" +
+				"  |\n" +
+				"2 | second line\n" +
+				"  |        ^\n",
+			))
+		})
+
+		Context("with context lines", func() {
+			BeforeEach(func() {
+				opts.ContextLines = 1
+			})
+
+			It("should display the lines before and after", func() {
+				Expect(res).To(Equal("" +
+					"error: some error\n" +
+					" --> testfile:2:8\n" +
+					"  |\n" +
+					"1 | first\n" +
+					"2 | second line\n" +
+					"  |        ^\n" +
+					"3 |     third\n",
+				))
+			})
+		})
+
+		Context("with colours", func() {
+			BeforeEach(func() {
+				opts.Color = true
+			})
+
+			It("should use ANSI colour codes", func() {
+				Expect(res).To(Equal("" +
+					"\x1b[1;31merror\x1b[0m\x1b[1m: some error\x1b[0m\n" +
+					" \x1b[1;34m-->\x1b[0m testfile:2:8\n" +
+					"  \x1b[1;34m|\x1b[0m\n" +
+					"\x1b[1;34m2 |\x1b[0m second line\n" +
+					"  \x1b[1;34m|\x1b[0m        \x1b[1;31m^\x1b[0m\n",
+				))
+			})
+		})
+	})
+
+	Context("when the error has a range", func() {
+		BeforeEach(func() {
+			errs = []parsley.Error{parsley.NewRangeError(parsley.Pos(7), parsley.Pos(13), errors.New("some error"))}
+		})
+
+		It("should underline the range", func() {
+			Expect(res).To(ContainSubstring("" +
+				"2 | second line\n" +
+				"  | ^^^^^^\n",
+			))
+		})
+	})
+
+	Context("when the error is an invalid value", func() {
+		BeforeEach(func() {
+			errs = []parsley.Error{parsley.NewInvalidValueError(parsley.Pos(7), parsley.Pos(13), "test", "second", nil)}
+		})
+
+		It("should underline the value", func() {
+			Expect(res).To(ContainSubstring("" +
+				"2 | second line\n" +
+				"  | ^^^^^^\n",
+			))
+		})
+	})
+
+	Context("when the error range spans multiple lines", func() {
+		BeforeEach(func() {
+			errs = []parsley.Error{parsley.NewRangeError(parsley.Pos(14), parsley.Pos(22), errors.New("some error"))}
+		})
+
+		It("should underline the range in all lines", func() {
+			Expect(res).To(ContainSubstring("" +
+				"2 | second line\n" +
+				"  |        ^^^^^\n" +
+				"3 |     third\n" +
+				"  | ^^^^^^\n",
+			))
+		})
+	})
+
+	Context("when the line contains tabs", func() {
+		BeforeEach(func() {
+			errs = []parsley.Error{parsley.NewErrorf(parsley.Pos(20), "some error")}
+		})
+
+		It("should align the caret to the expanded tabs", func() {
+			Expect(res).To(ContainSubstring("" +
+				"3 |     third\n" +
+				"  |     ^\n",
+			))
+		})
+
+		Context("with a custom tab width", func() {
+			BeforeEach(func() {
+				opts.TabWidth = 2
+			})
+
+			It("should use the given tab width", func() {
+				Expect(res).To(ContainSubstring("" +
+					"3 |   third\n" +
+					"  |   ^\n",
+				))
+			})
+		})
+	})
+
+	Context("when the error is at the end of the file", func() {
+		BeforeEach(func() {
+			errs = []parsley.Error{parsley.NewErrorf(parsley.Pos(len(data)+1), "some error")}
+		})
+
+		It("should mark the position after the last character", func() {
+			Expect(res).To(ContainSubstring("" +
+				"4 | fourth\n" +
+				"  |       ^\n",
+			))
+		})
+	})
+
+	Context("when there are multiple errors", func() {
+		BeforeEach(func() {
+			errs = []parsley.Error{
+				parsley.NewErrorf(parsley.Pos(1), "err1"),
+				parsley.NewErrorf(parsley.Pos(26), "err2"),
+			}
+		})
+
+		It("should render all errors", func() {
+			Expect(res).To(Equal("" +
+				"error: err1\n" +
+				" --> testfile:1:1\n" +
+				"  |\n" +
+				"1 | first\n" +
+				"  | ^\n" +
+				"\n" +
+				"error: err2\n" +
+				" --> testfile:4:1\n" +
+				"  |\n" +
+				"4 | fourth\n" +
+				"  | ^\n",
+			))
+		})
+	})
+
+	Context("when the error has an unknown position", func() {
+		BeforeEach(func() {
+			errs = []parsley.Error{parsley.NewErrorf(parsley.NilPos, "some error")}
+		})
+
+		It("should only render the message", func() {
+			Expect(res).To(Equal("error: some error\n"))
+		})
+	})
+
+	Context("when the file is not a source file", func() {
+		It("should only render the message and the position", func() {
+			file := &parsleyfakes.FakeFile{}
+			file.LenReturns(10)
+			position := &parsleyfakes.FakePosition{}
+			position.StringReturns("otherfile:1:2")
+			file.PositionReturns(position)
+			fs := parsley.NewFileSet(file)
+			Expect(fs.Diagnostics(opts, parsley.NewErrorf(parsley.Pos(2), "some error"))).To(Equal("" +
+				"error: some error\n" +
+				"--> otherfile:1:2\n",
+			))
+		})
+	})
+})
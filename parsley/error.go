@@ -13,6 +13,7 @@ package parsley
 
 import (
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -24,9 +25,11 @@ type Error interface {
 }
 
 type err struct {
-	cause error
-	msg   string
-	pos   Pos
+	cause  error
+	msg    string
+	pos    Pos
+	endPos Pos
+	fatal  bool
 }
 
 // NewError creates a new error with the given position
@@ -45,6 +48,28 @@ func NewError(pos Pos, cause error) Error {
 	}
 }
 
+// NewFatalError creates a new fatal error with the given position
+// A fatal error stops the parsing immediately, the combinators won't try any other alternatives.
+func NewFatalError(pos Pos, cause error) Error {
+	return &err{
+		cause: cause,
+		msg:   cause.Error(),
+		pos:   pos,
+		fatal: true,
+	}
+}
+
+// NewRangeError creates a new error for the range between the given positions
+// The end position is exclusive, it should point to the first character after the range.
+func NewRangeError(pos Pos, endPos Pos, cause error) Error {
+	return &err{
+		cause:  cause,
+		msg:    cause.Error(),
+		pos:    pos,
+		endPos: endPos,
+	}
+}
+
 // NewErrorf creates a new error with the given position and message
 func NewErrorf(pos Pos, format string, values ...interface{}) Error {
 	cause := fmt.Errorf(format, values...)
@@ -65,11 +90,45 @@ func (e *err) Pos() Pos {
 	return e.pos
 }
 
+// EndPos returns with the end position of the error's range or NilPos if the error has no range
+func (e *err) EndPos() Pos {
+	return e.endPos
+}
+
 // Cause returns with the original error
 func (e *err) Cause() error {
 	return e.cause
 }
 
+// Unwrap returns with the original error so the error chain can be checked with errors.Is and errors.As
+func (e *err) Unwrap() error {
+	return e.cause
+}
+
+// Fatal returns true if the error should stop the parsing immediately
+func (e *err) Fatal() bool {
+	return e.fatal
+}
+
+// IsFatal returns true if the given error should stop the parsing immediately
+func IsFatal(e Error) bool {
+	f, ok := e.(interface {
+		Fatal() bool
+	})
+	return ok && f.Fatal()
+}
+
+// EndPos returns with the end position of the error's range
+// If the error has no range then NilPos is returned.
+func EndPos(e Error) Pos {
+	if r, ok := e.(interface {
+		EndPos() Pos
+	}); ok {
+		return r.EndPos()
+	}
+	return NilPos
+}
+
 // WrapError wraps the given error in a error
 // If format contains the "{{err}}" placeholder it will be replaced with the original error message
 func WrapError(e Error, format string, values ...interface{}) Error {
@@ -78,8 +137,264 @@ func WrapError(e Error, format string, values ...interface{}) Error {
 		return e
 	}
 	return &err{
-		cause: e.Cause(),
-		pos:   e.Pos(),
-		msg:   strings.Replace(msg, "{{err}}", e.Error(), -1),
+		cause:  e.Cause(),
+		pos:    e.Pos(),
+		endPos: EndPos(e),
+		msg:    strings.Replace(msg, "{{err}}", e.Error(), -1),
+		fatal:  IsFatal(e),
+	}
+}
+
+// MoveError returns with an error which has the given position but otherwise behaves as the original error
+// The original error is kept in the error chain, so errors.Is and errors.As work on it, and the fatal flag and the
+// end position are also kept.
+func MoveError(e Error, pos Pos) Error {
+	if e.Pos() == pos {
+		return e
+	}
+	if m, ok := e.(*movedError); ok {
+		e = m.err
+	}
+	return &movedError{err: e, pos: pos}
+}
+
+// movedError is an error with a changed position
+type movedError struct {
+	err Error
+	pos Pos
+}
+
+// Error returns with the original error message
+func (e *movedError) Error() string {
+	return e.err.Error()
+}
+
+// Pos returns with the new position
+func (e *movedError) Pos() Pos {
+	return e.pos
+}
+
+// EndPos returns with the end position of the original error
+func (e *movedError) EndPos() Pos {
+	return EndPos(e.err)
+}
+
+// Cause returns with the cause of the original error
+func (e *movedError) Cause() error {
+	return e.err.Cause()
+}
+
+// Unwrap returns with the original error
+func (e *movedError) Unwrap() error {
+	return e.err
+}
+
+// Fatal returns true if the original error is fatal
+func (e *movedError) Fatal() bool {
+	return IsFatal(e.err)
+}
+
+// ExpectedError is an error for when none of the expected parsers matched at the given position
+// Found contains the description of the input at the error position if it's known.
+type ExpectedError struct {
+	Expected []string
+	Found    string
+	pos      Pos
+}
+
+// NewExpectedError creates a new error with the names of the expected parsers
+func NewExpectedError(pos Pos, expected ...string) *ExpectedError {
+	return &ExpectedError{
+		Expected: expected,
+		pos:      pos,
+	}
+}
+
+// Error returns with the error message listing all the expected parsers
+func (e *ExpectedError) Error() string {
+	if e.Found != "" {
+		return fmt.Sprintf("was expecting %s, found %s", joinNames(e.Expected), e.Found)
+	}
+	return "was expecting " + joinNames(e.Expected)
+}
+
+// Pos returns with the error's position
+func (e *ExpectedError) Pos() Pos {
+	return e.pos
+}
+
+// Cause returns with the error itself as there is no underlying error
+func (e *ExpectedError) Cause() error {
+	return e
+}
+
+// MergeErrors returns with the error which has the higher position
+// If the positions are the same then expected errors are merged, other errors are preferred over expected errors,
+// otherwise the second error is returned.
+func MergeErrors(e1, e2 Error) Error {
+	if e1 == nil {
+		return e2
+	}
+	if e2 == nil || e1.Pos() > e2.Pos() {
+		return e1
+	}
+	if e2.Pos() > e1.Pos() {
+		return e2
+	}
+
+	expected1, ok1 := e1.(*ExpectedError)
+	expected2, ok2 := e2.(*ExpectedError)
+	switch {
+	case ok1 && ok2:
+		return mergeExpectedErrors(expected1, expected2)
+	case ok2:
+		return e1
+	default:
+		return e2
+	}
+}
+
+func mergeExpectedErrors(e1, e2 *ExpectedError) *ExpectedError {
+	expected := make([]string, len(e1.Expected), len(e1.Expected)+len(e2.Expected))
+	copy(expected, e1.Expected)
+	for _, name := range e2.Expected {
+		found := false
+		for _, name2 := range e1.Expected {
+			if name == name2 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			expected = append(expected, name)
+		}
+	}
+	found := e1.Found
+	if found == "" {
+		found = e2.Found
+	}
+	if len(expected) == len(e1.Expected) && found == e1.Found {
+		return e1
+	}
+	return &ExpectedError{
+		Expected: expected,
+		Found:    found,
+		pos:      e1.pos,
+	}
+}
+
+// UnexpectedEOFError is an error for when the end of the input was reached but more input was expected
+// It matches io.ErrUnexpectedEOF with errors.Is.
+type UnexpectedEOFError struct {
+	Expected []string
+	pos      Pos
+}
+
+// NewUnexpectedEOFError creates a new unexpected end of input error with the names of the expected parsers
+func NewUnexpectedEOFError(pos Pos, expected ...string) *UnexpectedEOFError {
+	return &UnexpectedEOFError{
+		Expected: expected,
+		pos:      pos,
+	}
+}
+
+// Error returns with the error message
+func (e *UnexpectedEOFError) Error() string {
+	if len(e.Expected) == 0 {
+		return "unexpected end of input"
+	}
+	return "unexpected end of input, was expecting " + joinNames(e.Expected)
+}
+
+// Pos returns with the error's position
+func (e *UnexpectedEOFError) Pos() Pos {
+	return e.pos
+}
+
+// Cause returns with the error itself as there is no underlying error
+func (e *UnexpectedEOFError) Cause() error {
+	return e
+}
+
+// Is returns true if the target is io.ErrUnexpectedEOF
+func (e *UnexpectedEOFError) Is(target error) bool {
+	return target == io.ErrUnexpectedEOF
+}
+
+// InvalidValueError is an error for when the input was matched but the value is invalid (e.g. out of range)
+type InvalidValueError struct {
+	Type   string
+	Value  string
+	Err    error
+	pos    Pos
+	endPos Pos
+}
+
+// NewInvalidValueError creates a new invalid value error for the value between the given positions
+// The type is the name of the value type (e.g. "float"), err is the optional error returned by the conversion.
+func NewInvalidValueError(pos Pos, endPos Pos, typ string, value string, err error) *InvalidValueError {
+	return &InvalidValueError{
+		Type:   typ,
+		Value:  value,
+		Err:    err,
+		pos:    pos,
+		endPos: endPos,
+	}
+}
+
+// Error returns with the error message
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("invalid %s value encountered", e.Type)
+}
+
+// Pos returns with the error's position
+func (e *InvalidValueError) Pos() Pos {
+	return e.pos
+}
+
+// EndPos returns with the position after the invalid value
+func (e *InvalidValueError) EndPos() Pos {
+	return e.endPos
+}
+
+// Cause returns with the error itself so the value details are kept when the error is wrapped
+func (e *InvalidValueError) Cause() error {
+	return e
+}
+
+// Unwrap returns with the conversion error
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// DescribeError adds the description of the input at the error's position to an expected error
+// If the error position is at the end of the input then an unexpected end of input error is returned.
+// The input is described only if the reader implements the Describer interface.
+func DescribeError(r Reader, e Error) Error {
+	expectedErr, ok := e.(*ExpectedError)
+	if !ok || expectedErr.Found != "" {
+		return e
+	}
+	if r.IsEOF(e.Pos()) {
+		return NewUnexpectedEOFError(e.Pos(), expectedErr.Expected...)
+	}
+	if d, ok := r.(Describer); ok {
+		return &ExpectedError{
+			Expected: expectedErr.Expected,
+			Found:    d.Describe(e.Pos()),
+			pos:      e.Pos(),
+		}
+	}
+	return e
+}
+
+func joinNames(names []string) string {
+	switch len(names) {
+	case 0:
+		return "more input"
+	case 1:
+		return names[0]
+	default:
+		return strings.Join(names[0:len(names)-1], ", ") + " or " + names[len(names)-1]
 	}
 }
@@ -8,11 +8,14 @@ package parsley_test
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/parsley/parsleyfakes"
+	"github.com/sniperkit/snk.fork.parsley/text"
 )
 
 var _ = Describe("NewError", func() {
@@ -69,6 +72,116 @@ var _ = Describe("NewError", func() {
 	})
 })
 
+var _ = Describe("NewFatalError", func() {
+
+	var (
+		err   parsley.Error
+		cause error
+		pos   parsley.Pos
+	)
+
+	BeforeEach(func() {
+		pos = parsley.Pos(1)
+		cause = errors.New("some error")
+	})
+
+	JustBeforeEach(func() {
+		err = parsley.NewFatalError(pos, cause)
+	})
+
+	It("returns with the position", func() {
+		Expect(err.Pos()).To(BeIdenticalTo(pos))
+	})
+
+	It("returns with the error message of the cause", func() {
+		Expect(err.Error()).To(Equal("some error"))
+	})
+
+	It("returns with the original error", func() {
+		Expect(err.Cause()).To(BeIdenticalTo(cause))
+	})
+
+	It("is a fatal error", func() {
+		Expect(parsley.IsFatal(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("NewRangeError", func() {
+	var (
+		err   parsley.Error
+		cause error
+	)
+
+	BeforeEach(func() {
+		cause = errors.New("some error")
+		err = parsley.NewRangeError(parsley.Pos(1), parsley.Pos(3), cause)
+	})
+
+	It("returns with the position", func() {
+		Expect(err.Pos()).To(Equal(parsley.Pos(1)))
+	})
+
+	It("returns with the end position", func() {
+		Expect(parsley.EndPos(err)).To(Equal(parsley.Pos(3)))
+	})
+
+	It("returns with the original error", func() {
+		Expect(err.Cause()).To(BeIdenticalTo(cause))
+		Expect(err.Error()).To(Equal("some error"))
+	})
+
+	It("keeps the end position when wrapped", func() {
+		Expect(parsley.EndPos(parsley.WrapError(err, "wrapped: {{err}}"))).To(Equal(parsley.Pos(3)))
+	})
+})
+
+var _ = Describe("EndPos", func() {
+	It("returns NilPos if the error has no range", func() {
+		Expect(parsley.EndPos(parsley.NewErrorf(parsley.Pos(1), "some error"))).To(Equal(parsley.NilPos))
+		Expect(parsley.EndPos(parsley.NewExpectedError(parsley.Pos(1), "a"))).To(Equal(parsley.NilPos))
+	})
+})
+
+var _ = Describe("MoveError", func() {
+	It("should change only the position", func() {
+		cause := parsley.NewInvalidValueError(parsley.Pos(1), parsley.Pos(3), "integer", "ab", io.EOF)
+		err := parsley.MoveError(cause, parsley.Pos(2))
+		Expect(err.Pos()).To(Equal(parsley.Pos(2)))
+		Expect(err.Error()).To(Equal(cause.Error()))
+		Expect(err.Cause()).To(Equal(cause.Cause()))
+		Expect(parsley.EndPos(err)).To(Equal(parsley.Pos(3)))
+		Expect(errors.Is(err, io.EOF)).To(BeTrue())
+		var invalidErr *parsley.InvalidValueError
+		Expect(errors.As(err, &invalidErr)).To(BeTrue())
+	})
+
+	It("should keep the fatal flag", func() {
+		err := parsley.MoveError(parsley.NewFatalError(parsley.Pos(1), errors.New("fatal")), parsley.Pos(2))
+		Expect(parsley.IsFatal(err)).To(BeTrue())
+	})
+
+	It("should return the original error if the position is the same", func() {
+		cause := parsley.NewErrorf(parsley.Pos(1), "some error")
+		Expect(parsley.MoveError(cause, parsley.Pos(1))).To(BeIdenticalTo(cause))
+	})
+
+	It("should not wrap a moved error again", func() {
+		cause := parsley.NewErrorf(parsley.Pos(1), "some error")
+		err := parsley.MoveError(parsley.MoveError(cause, parsley.Pos(2)), parsley.Pos(3))
+		Expect(errors.Unwrap(err)).To(BeIdenticalTo(cause))
+	})
+})
+
+var _ = Describe("IsFatal", func() {
+	It("returns false for a regular error", func() {
+		Expect(parsley.IsFatal(parsley.NewErrorf(parsley.Pos(1), "some error"))).To(BeFalse())
+	})
+
+	It("returns true for a fatal error", func() {
+		Expect(parsley.IsFatal(parsley.NewFatalError(parsley.Pos(1), errors.New("some error")))).To(BeTrue())
+	})
+})
+
 var _ = Describe("NewErrorf", func() {
 
 	var (
@@ -146,6 +259,19 @@ var _ = Describe("WrapError", func() {
 		Expect(err.Cause()).To(BeIdenticalTo(cause))
 	})
 
+	It("should not be a fatal error", func() {
+		Expect(parsley.IsFatal(err)).To(BeFalse())
+	})
+
+	Context("when the error is a fatal error", func() {
+		BeforeEach(func() {
+			wrappedErr = parsley.NewFatalError(pos, cause)
+		})
+		It("should keep the error fatal", func() {
+			Expect(parsley.IsFatal(err)).To(BeTrue())
+		})
+	})
+
 	Context("when the error is not wrapped in a message", func() {
 		BeforeEach(func() {
 			format = ""
@@ -156,3 +282,212 @@ var _ = Describe("WrapError", func() {
 		})
 	})
 })
+
+var _ = Describe("NewExpectedError", func() {
+
+	var (
+		err      *parsley.ExpectedError
+		pos      parsley.Pos
+		expected []string
+	)
+
+	BeforeEach(func() {
+		pos = parsley.Pos(1)
+		expected = []string{"a"}
+	})
+
+	JustBeforeEach(func() {
+		err = parsley.NewExpectedError(pos, expected...)
+	})
+
+	It("implements parsley.Error", func() {
+		var _ parsley.Error = err
+	})
+
+	It("returns with the position", func() {
+		Expect(err.Pos()).To(BeIdenticalTo(pos))
+	})
+
+	It("returns with itself as the cause", func() {
+		Expect(err.Cause()).To(BeIdenticalTo(err))
+	})
+
+	It("returns with the error message", func() {
+		Expect(err.Error()).To(Equal("was expecting a"))
+	})
+
+	Context("when there are multiple expected parsers", func() {
+		BeforeEach(func() {
+			expected = []string{"a", "b", "c"}
+		})
+		It("lists all of them in the error message", func() {
+			Expect(err.Error()).To(Equal("was expecting a, b or c"))
+		})
+	})
+
+	Context("when the found input is set", func() {
+		BeforeEach(func() {
+			expected = []string{"a", "b"}
+		})
+		It("adds it to the error message", func() {
+			err.Found = `"c"`
+			Expect(err.Error()).To(Equal(`was expecting a or b, found "c"`))
+		})
+	})
+
+	Context("when there are no expected parsers", func() {
+		BeforeEach(func() {
+			expected = nil
+		})
+		It("returns with a generic error message", func() {
+			Expect(err.Error()).To(Equal("was expecting more input"))
+		})
+	})
+})
+
+var _ = Describe("MergeErrors", func() {
+
+	var (
+		err1, err2 parsley.Error
+	)
+
+	It("returns the other error if one of them is nil", func() {
+		err1 = parsley.NewErrorf(parsley.Pos(1), "err1")
+		Expect(parsley.MergeErrors(err1, nil)).To(BeIdenticalTo(err1))
+		Expect(parsley.MergeErrors(nil, err1)).To(BeIdenticalTo(err1))
+		Expect(parsley.MergeErrors(nil, nil)).To(BeNil())
+	})
+
+	It("returns the error with the higher position", func() {
+		err1 = parsley.NewExpectedError(parsley.Pos(1), "a")
+		err2 = parsley.NewExpectedError(parsley.Pos(2), "b")
+		Expect(parsley.MergeErrors(err1, err2)).To(BeIdenticalTo(err2))
+		Expect(parsley.MergeErrors(err2, err1)).To(BeIdenticalTo(err2))
+	})
+
+	Context("when the errors have the same position", func() {
+		It("merges the expected parsers without duplicates", func() {
+			err1 = parsley.NewExpectedError(parsley.Pos(1), "a", "b")
+			err2 = parsley.NewExpectedError(parsley.Pos(1), "b", "c")
+			err := parsley.MergeErrors(err1, err2)
+			Expect(err).To(Equal(parsley.NewExpectedError(parsley.Pos(1), "a", "b", "c")))
+		})
+
+		It("does not modify the original errors", func() {
+			err1 = parsley.NewExpectedError(parsley.Pos(1), "a")
+			err2 = parsley.NewExpectedError(parsley.Pos(1), "b")
+			parsley.MergeErrors(err1, err2)
+			Expect(err1).To(Equal(parsley.NewExpectedError(parsley.Pos(1), "a")))
+			Expect(err2).To(Equal(parsley.NewExpectedError(parsley.Pos(1), "b")))
+		})
+
+		It("prefers other errors over expected errors", func() {
+			err1 = parsley.NewErrorf(parsley.Pos(1), "err1")
+			err2 = parsley.NewExpectedError(parsley.Pos(1), "b")
+			Expect(parsley.MergeErrors(err1, err2)).To(BeIdenticalTo(err1))
+			Expect(parsley.MergeErrors(err2, err1)).To(BeIdenticalTo(err1))
+		})
+
+		It("returns the second error if neither is an expected error", func() {
+			err1 = parsley.NewErrorf(parsley.Pos(1), "err1")
+			err2 = parsley.NewErrorf(parsley.Pos(1), "err2")
+			Expect(parsley.MergeErrors(err1, err2)).To(BeIdenticalTo(err2))
+		})
+	})
+})
+
+var _ = Describe("UnexpectedEOFError", func() {
+	It("returns with the error message", func() {
+		Expect(parsley.NewUnexpectedEOFError(parsley.Pos(1))).To(MatchError("unexpected end of input"))
+		Expect(parsley.NewUnexpectedEOFError(parsley.Pos(1), "a", "b")).To(MatchError("unexpected end of input, was expecting a or b"))
+	})
+
+	It("returns with the position", func() {
+		Expect(parsley.NewUnexpectedEOFError(parsley.Pos(1)).Pos()).To(Equal(parsley.Pos(1)))
+	})
+
+	It("matches io.ErrUnexpectedEOF even if wrapped", func() {
+		err := parsley.WrapError(parsley.NewUnexpectedEOFError(parsley.Pos(1)), "failed: {{err}}")
+		Expect(errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue())
+		Expect(errors.Is(err, io.EOF)).To(BeFalse())
+	})
+})
+
+var _ = Describe("InvalidValueError", func() {
+	var (
+		err   *parsley.InvalidValueError
+		cause error
+	)
+
+	BeforeEach(func() {
+		cause = errors.New("out of range")
+		err = parsley.NewInvalidValueError(parsley.Pos(1), parsley.Pos(4), "integer", "123", cause)
+	})
+
+	It("returns with the error message", func() {
+		Expect(err).To(MatchError("invalid integer value encountered"))
+	})
+
+	It("returns with the position", func() {
+		Expect(err.Pos()).To(Equal(parsley.Pos(1)))
+	})
+
+	It("unwraps the original error", func() {
+		Expect(errors.Is(parsley.WrapError(err, "failed: {{err}}"), cause)).To(BeTrue())
+	})
+
+	It("can be extracted from a wrapped error", func() {
+		var invalidErr *parsley.InvalidValueError
+		Expect(errors.As(parsley.WrapError(err, "failed: {{err}}"), &invalidErr)).To(BeTrue())
+		Expect(invalidErr).To(BeIdenticalTo(err))
+	})
+})
+
+var _ = Describe("DescribeError", func() {
+	var (
+		r *parsleyfakes.FakeReader
+	)
+
+	BeforeEach(func() {
+		r = &parsleyfakes.FakeReader{}
+	})
+
+	It("does not change other errors", func() {
+		err := parsley.NewErrorf(parsley.Pos(1), "some error")
+		Expect(parsley.DescribeError(r, err)).To(BeIdenticalTo(err))
+	})
+
+	It("does not change the expected error if the reader can not describe the input", func() {
+		err := parsley.NewExpectedError(parsley.Pos(1), "a")
+		Expect(parsley.DescribeError(r, err)).To(BeIdenticalTo(err))
+	})
+
+	It("returns an unexpected end of input error at the end of the input", func() {
+		r.IsEOFReturns(true)
+		err := parsley.DescribeError(r, parsley.NewExpectedError(parsley.Pos(1), "a"))
+		Expect(err).To(Equal(parsley.NewUnexpectedEOFError(parsley.Pos(1), "a")))
+	})
+
+	It("adds the description of the input if the reader implements Describer", func() {
+		tr := text.NewReader(text.NewFile("testfile", []byte("abc")))
+		err := parsley.DescribeError(tr, parsley.NewExpectedError(parsley.Pos(2), "a"))
+		Expect(err).To(MatchError(`was expecting a, found "b"`))
+		Expect(err.Pos()).To(Equal(parsley.Pos(2)))
+	})
+})
+
+var _ = Describe("Unwrap", func() {
+	It("supports errors.Is on the original cause", func() {
+		cause := errors.New("some error")
+		err := parsley.WrapError(parsley.NewError(parsley.Pos(1), cause), "wrapped: {{err}}")
+		Expect(errors.Is(err, cause)).To(BeTrue())
+	})
+
+	It("supports errors.As for expected errors through wrapped errors", func() {
+		err := parsley.WrapError(parsley.NewExpectedError(parsley.Pos(1), "a"), "wrapped: {{err}}")
+		err = parsley.WrapError(err, "wrapped again: {{err}}")
+		var expectedErr *parsley.ExpectedError
+		Expect(errors.As(err, &expectedErr)).To(BeTrue())
+		Expect(expectedErr.Expected).To(Equal([]string{"a"}))
+	})
+})
@@ -20,6 +20,15 @@ type File interface {
 	SetOffset(int)
 }
 
+// SourceFile is an optional interface for files which are able to return with their source code lines
+// It's used for rendering source excerpts in diagnostics. The line numbers start from 1.
+//go:generate counterfeiter . SourceFile
+type SourceFile interface {
+	LineCount() int
+	LineNumber(int) int
+	Line(int) (int, []byte)
+}
+
 // FileSet contains multiple files
 type FileSet struct {
 	pos    int
@@ -58,8 +67,14 @@ func (fs *FileSet) Position(pos Pos) Position {
 		return NilPosition
 	}
 
+	f, offset := fs.file(pos)
+	return f.Position(offset)
+}
+
+// file returns with the file and the local offset for a given global position
+func (fs *FileSet) file(pos Pos) (File, int) {
 	i := sort.Search(len(fs.offset), func(i int) bool { return fs.offset[i] > int(pos) }) - 1
-	return fs.files[i].Position(int(pos) - fs.offset[i])
+	return fs.files[i], int(pos) - fs.offset[i]
 }
 
 // ErrorWithPosition creates an error with a human-readable position
@@ -0,0 +1,78 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parsley
+
+// ParserKind is the kind of a parser in the grammar structure
+type ParserKind string
+
+// Parser kinds
+// KindTerminal is used for the parsers which don't have child parsers or which don't expose their structure.
+// KindEmpty is used for the parsers which always match without consuming any input (e.g. parser.Nil).
+// KindWrapper is used for the parsers which apply a single child parser with some extra logic, e.g. skipping the
+// whitespaces.
+const (
+	KindTerminal  ParserKind = "terminal"
+	KindEmpty     ParserKind = "empty"
+	KindWrapper   ParserKind = "wrapper"
+	KindSeq       ParserKind = "seq"
+	KindSeqTry    ParserKind = "seq_try"
+	KindChoice    ParserKind = "choice"
+	KindAny       ParserKind = "any"
+	KindMany      ParserKind = "many"
+	KindMany1     ParserKind = "many1"
+	KindSepBy     ParserKind = "sep_by"
+	KindSepBy1    ParserKind = "sep_by1"
+	KindOptional  ParserKind = "optional"
+	KindMemoize   ParserKind = "memoize"
+	KindRecursive ParserKind = "recursive"
+	KindRecover   ParserKind = "recover"
+	KindDependent ParserKind = "dependent"
+	KindCount     ParserKind = "count"
+)
+
+// Composite is an optional interface for parsers to expose their structure
+// It's implemented by the combinators, so the grammar can be inspected (see the introspect package).
+// The children are returned in the order they are applied.
+type Composite interface {
+	Parser
+	Kind() ParserKind
+	Children() []Parser
+}
+
+// KindOf returns with the kind of the parser, KindTerminal is returned if it doesn't implement Composite
+func KindOf(p Parser) ParserKind {
+	if c, ok := p.(Composite); ok {
+		return c.Kind()
+	}
+	return KindTerminal
+}
+
+// ChildrenOf returns with the child parsers, nil is returned if the parser doesn't implement Composite
+func ChildrenOf(p Parser) []Parser {
+	if c, ok := p.(Composite); ok {
+		return c.Children()
+	}
+	return nil
+}
+
+// Counter is an optional interface for the parsers which apply their child parser a fixed number of times
+// It's implemented by the combinators (e.g. combinator.Count). If ok is false then the number of times is not fixed.
+type Counter interface {
+	Count() (n int, ok bool)
+}
+
+// Literal is an optional interface for terminals which match a fixed string
+// If word is true then the string is only matched if it's not followed by a word character (e.g. terminal.Word).
+// An empty value means the parser doesn't match a fixed string. It's used by introspect.Validate.
+type Literal interface {
+	Literal() (value string, word bool)
+}
+
+// Validator is an optional interface for parsers to check their configuration before parsing
+// E.g. a regular expression terminal can report an invalid expression. It's used by introspect.Validate.
+type Validator interface {
+	Validate() error
+}
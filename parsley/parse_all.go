@@ -0,0 +1,75 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parsley
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ParseAllOptions contains the settings for FileSet.ParseAll
+type ParseAllOptions struct {
+	// Workers is the number of files parsed in parallel, the default is runtime.GOMAXPROCS(0)
+	Workers int
+	// NewHistory creates a new history for every file
+	NewHistory func() History
+	// NewReader creates a new reader for the given file
+	NewReader func(File) Reader
+}
+
+// ParseResult is the result of parsing a file with FileSet.ParseAll
+type ParseResult struct {
+	File File
+	Node Node
+	Err  Error
+}
+
+// ParseAll parses all the files in the file set in parallel with the same parser
+// Every file is parsed with its own history and reader created by the factories in the options. The results are
+// returned in the same order as the files were added. If the context is cancelled then the remaining files will
+// have an error as described at ParseContext.
+//
+// The parser must not have any mutable state (see Parser) and the file set must not be modified during the parsing.
+func (fs *FileSet) ParseAll(ctx context.Context, p Parser, opts ParseAllOptions) []ParseResult {
+	if opts.NewHistory == nil {
+		panic(errors.New("NewHistory can not be nil"))
+	}
+	if opts.NewReader == nil {
+		panic(errors.New("NewReader can not be nil"))
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(fs.files) {
+		workers = len(fs.files)
+	}
+
+	results := make([]ParseResult, len(fs.files))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				f := fs.files[i]
+				node, err := ParseContext(ctx, opts.NewHistory(), opts.NewReader(f), p)
+				results[i] = ParseResult{File: f, Node: node, Err: err}
+			}
+		}()
+	}
+	for i := range fs.files {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results
+}
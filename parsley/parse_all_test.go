@@ -0,0 +1,153 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parsley_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/text/terminal"
+)
+
+// newSumParser returns with a left-recursive parser for sums, e.g. 1 + 2 + 3
+func newSumParser() parsley.Parser {
+	var sum parser.NamedFunc
+	value := ast.InterpreterFunc(func(ctx interface{}, nodes []parsley.Node) (interface{}, parsley.Error) {
+		v1, _ := nodes[0].Value(ctx)
+		v2, _ := nodes[2].Value(ctx)
+		return v1.(int) + v2.(int), nil
+	})
+	integer := text.LeftTrim(terminal.Integer(), text.WsSpaces)
+	sum = *combinator.Memoize(combinator.Any("sum",
+		combinator.Seq("SUM", "sum", &sum, text.LeftTrim(terminal.Rune('+'), text.WsSpaces), integer).Bind(value),
+		terminal.Integer(),
+	))
+	return combinator.Sentence(text.RightTrim(&sum, text.WsSpaces))
+}
+
+// newParseAllOptions returns with the options to parse text files
+func newParseAllOptions() parsley.ParseAllOptions {
+	return parsley.ParseAllOptions{
+		NewHistory: func() parsley.History { return parser.NewHistory() },
+		NewReader:  func(f parsley.File) parsley.Reader { return text.NewReader(f.(*text.File)) },
+	}
+}
+
+// Let's parse multiple files in parallel with the same grammar.
+func ExampleFileSet_ParseAll() {
+	fs := parsley.NewFileSet(
+		text.NewFile("a.file", []byte("1 + 2")),
+		text.NewFile("b.file", []byte("3 + 4 + 5")),
+		text.NewFile("c.file", []byte("6 +")),
+	)
+	for _, res := range fs.ParseAll(context.Background(), newSumParser(), newParseAllOptions()) {
+		if res.Err != nil {
+			fmt.Println(fs.ErrorWithPosition(res.Err))
+			continue
+		}
+		value, _ := res.Node.Value(nil)
+		fmt.Println(value)
+	}
+	// Output:
+	// 3
+	// 12
+	// failed to parse the input: unexpected end of input, was expecting integer value at c.file:1:4
+}
+
+var _ = Describe("ParseAll", func() {
+	var (
+		fs   *parsley.FileSet
+		sums []int
+		opts parsley.ParseAllOptions
+	)
+
+	BeforeEach(func() {
+		fs = parsley.NewFileSet()
+		sums = nil
+		for i := 1; i <= 50; i++ {
+			values := make([]string, i)
+			sum := 0
+			for j := range values {
+				values[j] = strconv.Itoa(j)
+				sum += j
+			}
+			fs.AddFile(text.NewFile(fmt.Sprintf("%d.file", i), []byte(strings.Join(values, " + "))))
+			sums = append(sums, sum)
+		}
+		opts = newParseAllOptions()
+	})
+
+	It("should parse all the files in order", func() {
+		opts.Workers = 4
+		results := fs.ParseAll(context.Background(), newSumParser(), opts)
+		Expect(results).To(HaveLen(len(sums)))
+		for i, res := range results {
+			Expect(res.Err).ToNot(HaveOccurred())
+			Expect(res.File.(*text.File).Filename()).To(Equal(fmt.Sprintf("%d.file", i+1)))
+			Expect(res.Node.Value(nil)).To(Equal(sums[i]))
+		}
+	})
+
+	It("should use a default number of workers", func() {
+		results := fs.ParseAll(context.Background(), newSumParser(), opts)
+		Expect(results).To(HaveLen(len(sums)))
+		for _, res := range results {
+			Expect(res.Err).ToNot(HaveOccurred())
+		}
+	})
+
+	It("should return an error for all the files if the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, res := range fs.ParseAll(ctx, newSumParser(), opts) {
+			Expect(res.Node).To(BeNil())
+			Expect(res.Err).To(MatchError("parsing was aborted: context canceled"))
+		}
+	})
+
+	It("should return an empty result for an empty file set", func() {
+		Expect(parsley.NewFileSet().ParseAll(context.Background(), newSumParser(), opts)).To(BeEmpty())
+	})
+
+	It("should panic if a factory is missing", func() {
+		Expect(func() {
+			fs.ParseAll(context.Background(), newSumParser(), parsley.ParseAllOptions{})
+		}).To(Panic())
+	})
+
+	It("should allow to share the parser and the files between goroutines", func() {
+		p := newSumParser()
+		var wg sync.WaitGroup
+		results := make([][]parsley.ParseResult, 4)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i] = fs.ParseAll(context.Background(), p, opts)
+				for _, res := range results[i] {
+					fs.Position(res.Node.ReaderPos())
+				}
+			}(i)
+		}
+		wg.Wait()
+		for _, res := range results {
+			for i, r := range res {
+				Expect(r.Node.Value(nil)).To(Equal(sums[i]))
+			}
+		}
+	})
+})
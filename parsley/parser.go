@@ -10,6 +10,9 @@ import (
 )
 
 // Parser defines a parser interface
+// A parser must not change its own state during parsing, all the state of a parsing is kept in the history and the
+// reader. This way the same parser (grammar) can be used by multiple goroutines at the same time as long as every
+// parsing has its own history and reader.
 //go:generate counterfeiter . Parser
 type Parser interface {
 	Parse(h History, leftRecCtx data.IntMap, r Reader, pos Pos) (Node, Error, data.IntSet)
@@ -25,10 +28,95 @@ type Result struct {
 }
 
 // History records information about parser calls
+// A history belongs to a single parsing and it's not safe for concurrent use.
+// RegisterCall is called before every parser call. If it returns with an error the parsing will be stopped
+// and the error will be returned.
+// Enter and Leave are called when a recursive combinator (e.g. Seq, Many or SepBy) starts and finishes parsing,
+// so the history can track the nesting depth.
 //go:generate counterfeiter . History
 type History interface {
-	SaveResult(parserIndex int, pos Pos, result *Result)
+	SaveResult(parserIndex int, pos Pos, result *Result) Error
 	GetResult(parserIndex int, pos Pos, leftRecCtx data.IntMap) (*Result, bool)
-	RegisterCall()
+	RegisterCall(pos Pos) Error
 	CallCount() int
+	Enter(pos Pos) Error
+	Leave()
+}
+
+// Tracer is an optional History interface to trace the parser calls
+// TraceCall is called before a parser is called and TraceResult is called with the parser's results.
+type Tracer interface {
+	TraceCall(p Parser, pos Pos)
+	TraceResult(p Parser, pos Pos, node Node, err Error)
+}
+
+// Committer is an optional History interface to commit the parsing at a position
+// Commit is called when the parsing can't backtrack before the given position any more (e.g. by combinator.Cut), so
+// the history may drop the memoized results before it.
+type Committer interface {
+	Commit(pos Pos)
+}
+
+// HistoryWrapper is an optional History interface for histories which wrap an other history
+// The optional History interfaces (e.g. Tracer and Committer) are also looked up in the wrapped histories, so a wrapper
+// only has to implement the methods it changes. The calls are reported to all the tracers in the chain, so e.g. a trace
+// and a profile history can be stacked.
+type HistoryWrapper interface {
+	Unwrap() History
+}
+
+// Call calls the given parser and reports the call to the history and to the wrapped histories which implement the
+// Tracer interface
+// The combinators should call their child parsers with Call so the parser calls can be traced.
+func Call(h History, p Parser, leftRecCtx data.IntMap, r Reader, pos Pos) (Node, Error, data.IntSet) {
+	t, ok := tracerOf(h)
+	if !ok {
+		return p.Parse(h, leftRecCtx, r, pos)
+	}
+	for ; t != nil; t, _ = tracerOf(unwrap(t)) {
+		t.TraceCall(p, pos)
+	}
+	node, err, cp := p.Parse(h, leftRecCtx, r, pos)
+	for t, _ = tracerOf(h); t != nil; t, _ = tracerOf(unwrap(t)) {
+		t.TraceResult(p, pos, node, err)
+	}
+	return node, err, cp
+}
+
+// Commit commits the history at the given position if it implements the Committer interface
+func Commit(h History, pos Pos) {
+	for h != nil {
+		if c, ok := h.(Committer); ok {
+			c.Commit(pos)
+			return
+		}
+		w, ok := h.(HistoryWrapper)
+		if !ok {
+			return
+		}
+		h = w.Unwrap()
+	}
+}
+
+// unwrap returns with the wrapped history or nil if the tracer doesn't wrap an other history
+func unwrap(t Tracer) History {
+	if w, ok := t.(HistoryWrapper); ok {
+		return w.Unwrap()
+	}
+	return nil
+}
+
+// tracerOf returns with the history or the first wrapped history which implements the Tracer interface
+func tracerOf(h History) (Tracer, bool) {
+	for h != nil {
+		if t, ok := h.(Tracer); ok {
+			return t, true
+		}
+		w, ok := h.(HistoryWrapper)
+		if !ok {
+			return nil, false
+		}
+		h = w.Unwrap()
+	}
+	return nil, false
 }
@@ -12,21 +12,28 @@ Sniperkit-Bot
 package parsley
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/sniperkit/snk.fork.parsley/data"
 )
 
 // Parse parses the given input and returns with the root node of the AST. It expects a reader and the root parser.
 // If there are multiple possible parse trees only the first one is returned.
+// If the parsing was stopped by a fatal error then the error is returned as is.
 func Parse(h History, r Reader, p Parser) (Node, Error) {
-	h.RegisterCall()
-	node, err, _ := p.Parse(h, data.EmptyIntMap, r, r.Pos(0))
+	pos := r.Pos(0)
+	if err := h.RegisterCall(pos); err != nil {
+		return nil, err
+	}
+	node, err, _ := Call(h, p, data.EmptyIntMap, r, pos)
+	if err != nil && IsFatal(err) {
+		return nil, err
+	}
 	if node == nil {
-		if err != nil {
-			return nil, WrapError(err, "failed to parse the input: {{err}}")
+		if err == nil {
+			err = NewExpectedError(pos, p.Name())
 		}
-		return nil, NewError(r.Pos(0), fmt.Errorf("failed to parse the input: was expecting %s", p.Name()))
+		return nil, WrapError(DescribeError(r, err), "failed to parse the input: {{err}}")
 	}
 	return node, nil
 }
@@ -41,3 +48,34 @@ func Evaluate(h History, r Reader, p Parser, ctx interface{}) (interface{}, Erro
 	value, evalErr := node.Value(ctx)
 	return value, evalErr
 }
+
+// ParseContext parses the given input the same way as Parse but it stops as soon as the context is cancelled
+// or its deadline is exceeded. In this case the returned error will contain the position where the parsing was aborted.
+func ParseContext(ctx context.Context, h History, r Reader, p Parser) (Node, Error) {
+	return Parse(&contextHistory{History: h, ctx: ctx}, r, p)
+}
+
+// EvaluateContext parses and evaluates the given input the same way as Evaluate but it stops parsing as soon as
+// the context is cancelled or its deadline is exceeded.
+func EvaluateContext(ctx context.Context, h History, r Reader, p Parser, evalCtx interface{}) (interface{}, Error) {
+	return Evaluate(&contextHistory{History: h, ctx: ctx}, r, p, evalCtx)
+}
+
+// contextHistory checks the context for cancellation before every parser call
+type contextHistory struct {
+	History
+	ctx context.Context
+}
+
+// RegisterCall returns with a fatal error if the context is done, otherwise it calls the wrapped history
+func (h *contextHistory) RegisterCall(pos Pos) Error {
+	if err := h.ctx.Err(); err != nil {
+		return WrapError(NewFatalError(pos, err), "parsing was aborted: {{err}}")
+	}
+	return h.History.RegisterCall(pos)
+}
+
+// Unwrap returns with the wrapped history
+func (h *contextHistory) Unwrap() History {
+	return h.History
+}
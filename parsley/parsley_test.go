@@ -12,7 +12,11 @@ Sniperkit-Bot
 package parsley_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -147,6 +151,30 @@ var _ = Describe("Parse", func() {
 		})
 	})
 
+	Context("when parser returned with a result and a fatal error", func() {
+		BeforeEach(func() {
+			parserErr = parsley.NewFatalError(parsley.Pos(2), errors.New("fatal error"))
+		})
+		It("should return the original error", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(BeIdenticalTo(parserErr))
+		})
+	})
+
+	Context("when the history returns with an error", func() {
+		var historyErr parsley.Error
+
+		BeforeEach(func() {
+			historyErr = parsley.NewFatalError(parsley.Pos(1), errors.New("fatal error"))
+			h.RegisterCallReturns(historyErr)
+		})
+		It("should not call the parser and return the error", func() {
+			Expect(p.ParseCallCount()).To(Equal(0))
+			Expect(res).To(BeNil())
+			Expect(err).To(BeIdenticalTo(historyErr))
+		})
+	})
+
 	Context("when the result node is nil", func() {
 		BeforeEach(func() {
 			parserRes = nil
@@ -160,6 +188,142 @@ var _ = Describe("Parse", func() {
 	})
 })
 
+var _ = Describe("ParseContext", func() {
+	var (
+		ctx       context.Context
+		cancel    context.CancelFunc
+		h         *parsleyfakes.FakeHistory
+		r         *parsleyfakes.FakeReader
+		p         *parsleyfakes.FakeParser
+		res       parsley.Node
+		err       parsley.Error
+		parserRes parsley.Node
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		h = &parsleyfakes.FakeHistory{}
+		r = &parsleyfakes.FakeReader{}
+		r.PosReturns(parsley.Pos(1))
+		p = &parsleyfakes.FakeParser{}
+		p.NameReturns("p1")
+		parserRes = &parsleyfakes.FakeNode{}
+		p.ParseStub = func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+			if err := h.RegisterCall(parsley.Pos(2)); err != nil {
+				return nil, err, data.EmptyIntSet
+			}
+			return parserRes, nil, data.EmptyIntSet
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	JustBeforeEach(func() {
+		res, err = parsley.ParseContext(ctx, h, r, p)
+	})
+
+	It("should return the result of the parser", func() {
+		Expect(res).To(BeEquivalentTo(parserRes))
+		Expect(err).To(BeNil())
+	})
+
+	It("should register the calls in the original history", func() {
+		Expect(h.RegisterCallCallCount()).To(Equal(2))
+		Expect(h.RegisterCallArgsForCall(0)).To(Equal(parsley.Pos(1)))
+		Expect(h.RegisterCallArgsForCall(1)).To(Equal(parsley.Pos(2)))
+	})
+
+	Context("when the context is already cancelled", func() {
+		BeforeEach(func() {
+			cancel()
+		})
+		It("should not call the parser and return an error", func() {
+			Expect(p.ParseCallCount()).To(Equal(0))
+			Expect(res).To(BeNil())
+			Expect(err).To(MatchError("parsing was aborted: context canceled"))
+			Expect(err.Pos()).To(Equal(parsley.Pos(1)))
+			Expect(err.Cause()).To(Equal(context.Canceled))
+			Expect(parsley.IsFatal(err)).To(BeTrue())
+		})
+	})
+
+	Context("when the context is cancelled during parsing", func() {
+		BeforeEach(func() {
+			h.RegisterCallStub = func(pos parsley.Pos) parsley.Error {
+				cancel()
+				return nil
+			}
+		})
+		It("should return an error with the position where the parsing was aborted", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(MatchError("parsing was aborted: context canceled"))
+			Expect(err.Pos()).To(Equal(parsley.Pos(2)))
+		})
+	})
+
+	Context("when the deadline is exceeded", func() {
+		BeforeEach(func() {
+			ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		})
+		It("should return an error", func() {
+			Expect(res).To(BeNil())
+			Expect(err).To(MatchError("parsing was aborted: context deadline exceeded"))
+			Expect(err.Cause()).To(Equal(context.DeadlineExceeded))
+		})
+	})
+})
+
+var _ = Describe("EvaluateContext", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		h      *parsleyfakes.FakeHistory
+		r      *parsleyfakes.FakeReader
+		p      *parsleyfakes.FakeParser
+		node   *parsleyfakes.FakeNode
+		val    interface{}
+		err    parsley.Error
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		h = &parsleyfakes.FakeHistory{}
+		r = &parsleyfakes.FakeReader{}
+		r.PosReturns(parsley.Pos(1))
+		p = &parsleyfakes.FakeParser{}
+		node = &parsleyfakes.FakeNode{}
+		node.ValueReturns("value", nil)
+		p.ParseReturns(node, nil, data.EmptyIntSet)
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	JustBeforeEach(func() {
+		val, err = parsley.EvaluateContext(ctx, h, r, p, "context")
+	})
+
+	It("should return the value of the node", func() {
+		Expect(val).To(Equal("value"))
+		Expect(err).To(BeNil())
+		Expect(node.ValueArgsForCall(0)).To(Equal("context"))
+	})
+
+	Context("when the context is cancelled", func() {
+		BeforeEach(func() {
+			cancel()
+		})
+		It("should return an error", func() {
+			Expect(val).To(BeNil())
+			Expect(err).To(MatchError("parsing was aborted: context canceled"))
+			Expect(node.ValueCallCount()).To(Equal(0))
+		})
+	})
+})
+
 var _ = Describe("Evaluate", func() {
 	var (
 		h          *parsleyfakes.FakeHistory
@@ -315,4 +479,172 @@ var _ = Describe("Parsley", func() {
 		Expect(h.CallCount()).To(Equal(237770))
 
 	})
+
+	It("should list all the expected parsers at the furthest position", func() {
+		value := combinator.Choice("value",
+			terminal.Integer(),
+			terminal.String(false),
+			combinator.Seq("ARRAY", "array",
+				terminal.Rune('['),
+				terminal.Rune(']'),
+			),
+		)
+		p := combinator.Seq("ASSIGN", "assignment",
+			terminal.Word("x", "x"),
+			terminal.Rune('='),
+			value,
+		)
+
+		r := text.NewReader(text.NewFile("testfile", []byte("x=?")))
+		_, err := parsley.Parse(parser.NewHistory(), r, combinator.Sentence(p))
+		Expect(err).To(MatchError(`failed to parse the input: was expecting integer value, string value or "[", found "?"`))
+		Expect(err.Pos()).To(Equal(r.Pos(2)))
+
+		var expectedErr *parsley.ExpectedError
+		Expect(errors.As(err, &expectedErr)).To(BeTrue())
+		Expect(expectedErr.Expected).To(Equal([]string{"integer value", "string value", `"["`}))
+		Expect(expectedErr.Found).To(Equal(`"?"`))
+	})
+
+	It("should return an unexpected end of input error if the input is incomplete", func() {
+		p := combinator.Seq("ADD", "addition",
+			terminal.Integer(),
+			terminal.Rune('+'),
+			terminal.Integer(),
+		)
+
+		r := text.NewReader(text.NewFile("testfile", []byte("1+")))
+		_, err := parsley.Parse(parser.NewHistory(), r, combinator.Sentence(p))
+		Expect(err).To(MatchError("failed to parse the input: unexpected end of input, was expecting integer value"))
+		Expect(errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue())
+	})
+
+	It("should return a partial tree with all the errors when recovering from syntax errors", func() {
+		assignment := combinator.Seq("ASSIGN", "assignment",
+			text.LeftTrim(terminal.Word("x", "x"), text.WsSpacesNl),
+			text.LeftTrim(terminal.Rune('='), text.WsSpaces),
+			text.LeftTrim(terminal.Integer(), text.WsSpaces),
+		)
+		p := combinator.SepBy(
+			combinator.Recover(assignment, terminal.Rune(';')),
+			terminal.Rune(';'),
+		)
+
+		r := text.NewReader(text.NewFile("testfile", []byte("x = 1;\nx = ;\nx = 3;\nx 4")))
+		node, err := parsley.Parse(parser.NewHistory(), r, combinator.Sentence(p))
+		Expect(err).ToNot(HaveOccurred())
+
+		errs := ast.Errors(node)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0]).To(MatchError(`was expecting integer value, found ";"`))
+		Expect(errs[0].Pos()).To(Equal(r.Pos(11)))
+		Expect(errs[1]).To(MatchError(`was expecting "=", found "4"`))
+		Expect(errs[1].Pos()).To(Equal(r.Pos(22)))
+	})
+
+	Context("when the history has limits", func() {
+		var (
+			p parser.NamedFunc
+			r *text.Reader
+		)
+
+		BeforeEach(func() {
+			value := combinator.Memoize(combinator.Any("value",
+				terminal.Integer(),
+				&p,
+			))
+			p = *combinator.Memoize(combinator.Seq("ADD", "addition",
+				value,
+				terminal.Rune('+'),
+				value,
+			))
+			r = text.NewReader(text.NewFile("testfile", []byte("1+2+3+4+5+6+7+8+9+10")))
+		})
+
+		It("should stop when the call limit is exceeded", func() {
+			h := parser.NewHistoryWithLimits(parser.Limits{MaxCalls: 1000})
+			_, err := parsley.Parse(h, r, combinator.Sentence(&p))
+			Expect(err).To(MatchError("exceeded the maximum number of parser calls (1000)"))
+			Expect(err).To(BeAssignableToTypeOf(&parser.LimitError{}))
+			Expect(h.CallCount()).To(Equal(1001))
+		})
+
+		It("should stop when the result limit is exceeded", func() {
+			h := parser.NewHistoryWithLimits(parser.Limits{MaxResults: 10})
+			_, err := parsley.Parse(h, r, combinator.Sentence(&p))
+			Expect(err).To(MatchError("exceeded the maximum number of memoized results (10)"))
+		})
+
+		It("should stop when the nesting depth limit is exceeded", func() {
+			h := parser.NewHistoryWithLimits(parser.Limits{MaxDepth: 3})
+			_, err := parsley.Parse(h, r, combinator.Sentence(&p))
+			Expect(err).To(MatchError("exceeded the maximum nesting depth (3)"))
+		})
+	})
+})
+
+// tracingHistory records the traced parser names
+type tracingHistory struct {
+	parsley.History
+	calls   []string
+	results []string
+}
+
+func (h *tracingHistory) TraceCall(p parsley.Parser, pos parsley.Pos) {
+	h.calls = append(h.calls, p.Name())
+}
+
+func (h *tracingHistory) TraceResult(p parsley.Parser, pos parsley.Pos, node parsley.Node, err parsley.Error) {
+	h.results = append(h.results, p.Name())
+}
+
+var _ = Describe("Call", func() {
+	var (
+		p *parsleyfakes.FakeParser
+		r *parsleyfakes.FakeReader
+	)
+
+	BeforeEach(func() {
+		p = &parsleyfakes.FakeParser{}
+		p.NameReturns("p1")
+		p.ParseReturns(ast.NilNode(1), nil, data.EmptyIntSet)
+		r = &parsleyfakes.FakeReader{}
+	})
+
+	It("should call the parser", func() {
+		h := parser.NewHistory()
+		node, err, cp := parsley.Call(h, p, data.EmptyIntMap, r, 1)
+		Expect(node).To(Equal(ast.NilNode(1)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cp).To(Equal(data.EmptyIntSet))
+		Expect(p.ParseCallCount()).To(Equal(1))
+	})
+
+	It("should report the call if the history is a tracer", func() {
+		h := &tracingHistory{History: parser.NewHistory()}
+		parsley.Call(h, p, data.EmptyIntMap, r, 1)
+		Expect(h.calls).To(Equal([]string{"p1"}))
+		Expect(h.results).To(Equal([]string{"p1"}))
+	})
+
+	It("should trace and commit through ParseContext", func() {
+		store := parser.NewWindowMemoStore(0)
+		h := parser.NewTraceHistory(parser.NewHistory().WithMemoStore(store))
+		keyword := combinator.Memoize(terminal.Word("function", "function"))
+		ident := text.LeftTrim(terminal.Regexp("IDENT", "identifier", "[a-z]+", 0), text.WsSpaces)
+		p := combinator.Seq("FUNCTION", "function", keyword, combinator.Cut(), ident)
+		f := text.NewFile("", []byte("function foo"))
+		_, err := parsley.ParseContext(context.Background(), h, text.NewReader(f), p)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(h.Trace()).To(HaveLen(1))
+		Expect(h.Trace()[0].Name).To(Equal("function"))
+		Expect(store.Len()).To(Equal(0))
+	})
+
+	It("should report the calls through ParseContext", func() {
+		h := &tracingHistory{History: parser.NewHistory()}
+		r.PosReturns(1)
+		parsley.ParseContext(context.Background(), h, r, p)
+		Expect(h.calls).To(Equal([]string{"p1"}))
+	})
 })
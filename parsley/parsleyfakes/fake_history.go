@@ -14,13 +14,19 @@ import (
 )
 
 type FakeHistory struct {
-	SaveResultStub        func(parserIndex int, pos parsley.Pos, result *parsley.Result)
+	SaveResultStub        func(parserIndex int, pos parsley.Pos, result *parsley.Result) parsley.Error
 	saveResultMutex       sync.RWMutex
 	saveResultArgsForCall []struct {
 		parserIndex int
 		pos         parsley.Pos
 		result      *parsley.Result
 	}
+	saveResultReturns struct {
+		result1 parsley.Error
+	}
+	saveResultReturnsOnCall map[int]struct {
+		result1 parsley.Error
+	}
 	GetResultStub        func(parserIndex int, pos parsley.Pos, leftRecCtx data.IntMap) (*parsley.Result, bool)
 	getResultMutex       sync.RWMutex
 	getResultArgsForCall []struct {
@@ -36,24 +42,47 @@ type FakeHistory struct {
 		result1 *parsley.Result
 		result2 bool
 	}
-	RegisterCallStub        func()
+	RegisterCallStub        func(pos parsley.Pos) parsley.Error
 	registerCallMutex       sync.RWMutex
-	registerCallArgsForCall []struct{}
-	CallCountStub           func() int
-	callCountMutex          sync.RWMutex
-	callCountArgsForCall    []struct{}
-	callCountReturns        struct {
+	registerCallArgsForCall []struct {
+		pos parsley.Pos
+	}
+	registerCallReturns struct {
+		result1 parsley.Error
+	}
+	registerCallReturnsOnCall map[int]struct {
+		result1 parsley.Error
+	}
+	CallCountStub        func() int
+	callCountMutex       sync.RWMutex
+	callCountArgsForCall []struct{}
+	callCountReturns     struct {
 		result1 int
 	}
 	callCountReturnsOnCall map[int]struct {
 		result1 int
 	}
+	EnterStub        func(pos parsley.Pos) parsley.Error
+	enterMutex       sync.RWMutex
+	enterArgsForCall []struct {
+		pos parsley.Pos
+	}
+	enterReturns struct {
+		result1 parsley.Error
+	}
+	enterReturnsOnCall map[int]struct {
+		result1 parsley.Error
+	}
+	LeaveStub        func()
+	leaveMutex       sync.RWMutex
+	leaveArgsForCall []struct{}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeHistory) SaveResult(parserIndex int, pos parsley.Pos, result *parsley.Result) {
+func (fake *FakeHistory) SaveResult(parserIndex int, pos parsley.Pos, result *parsley.Result) parsley.Error {
 	fake.saveResultMutex.Lock()
+	ret, specificReturn := fake.saveResultReturnsOnCall[len(fake.saveResultArgsForCall)]
 	fake.saveResultArgsForCall = append(fake.saveResultArgsForCall, struct {
 		parserIndex int
 		pos         parsley.Pos
@@ -62,8 +91,12 @@ func (fake *FakeHistory) SaveResult(parserIndex int, pos parsley.Pos, result *pa
 	fake.recordInvocation("SaveResult", []interface{}{parserIndex, pos, result})
 	fake.saveResultMutex.Unlock()
 	if fake.SaveResultStub != nil {
-		fake.SaveResultStub(parserIndex, pos, result)
+		return fake.SaveResultStub(parserIndex, pos, result)
 	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.saveResultReturns.result1
 }
 
 func (fake *FakeHistory) SaveResultCallCount() int {
@@ -78,6 +111,25 @@ func (fake *FakeHistory) SaveResultArgsForCall(i int) (int, parsley.Pos, *parsle
 	return fake.saveResultArgsForCall[i].parserIndex, fake.saveResultArgsForCall[i].pos, fake.saveResultArgsForCall[i].result
 }
 
+func (fake *FakeHistory) SaveResultReturns(result1 parsley.Error) {
+	fake.SaveResultStub = nil
+	fake.saveResultReturns = struct {
+		result1 parsley.Error
+	}{result1}
+}
+
+func (fake *FakeHistory) SaveResultReturnsOnCall(i int, result1 parsley.Error) {
+	fake.SaveResultStub = nil
+	if fake.saveResultReturnsOnCall == nil {
+		fake.saveResultReturnsOnCall = make(map[int]struct {
+			result1 parsley.Error
+		})
+	}
+	fake.saveResultReturnsOnCall[i] = struct {
+		result1 parsley.Error
+	}{result1}
+}
+
 func (fake *FakeHistory) GetResult(parserIndex int, pos parsley.Pos, leftRecCtx data.IntMap) (*parsley.Result, bool) {
 	fake.getResultMutex.Lock()
 	ret, specificReturn := fake.getResultReturnsOnCall[len(fake.getResultArgsForCall)]
@@ -131,14 +183,21 @@ func (fake *FakeHistory) GetResultReturnsOnCall(i int, result1 *parsley.Result,
 	}{result1, result2}
 }
 
-func (fake *FakeHistory) RegisterCall() {
+func (fake *FakeHistory) RegisterCall(pos parsley.Pos) parsley.Error {
 	fake.registerCallMutex.Lock()
-	fake.registerCallArgsForCall = append(fake.registerCallArgsForCall, struct{}{})
-	fake.recordInvocation("RegisterCall", []interface{}{})
+	ret, specificReturn := fake.registerCallReturnsOnCall[len(fake.registerCallArgsForCall)]
+	fake.registerCallArgsForCall = append(fake.registerCallArgsForCall, struct {
+		pos parsley.Pos
+	}{pos})
+	fake.recordInvocation("RegisterCall", []interface{}{pos})
 	fake.registerCallMutex.Unlock()
 	if fake.RegisterCallStub != nil {
-		fake.RegisterCallStub()
+		return fake.RegisterCallStub(pos)
+	}
+	if specificReturn {
+		return ret.result1
 	}
+	return fake.registerCallReturns.result1
 }
 
 func (fake *FakeHistory) RegisterCallCallCount() int {
@@ -147,6 +206,31 @@ func (fake *FakeHistory) RegisterCallCallCount() int {
 	return len(fake.registerCallArgsForCall)
 }
 
+func (fake *FakeHistory) RegisterCallArgsForCall(i int) parsley.Pos {
+	fake.registerCallMutex.RLock()
+	defer fake.registerCallMutex.RUnlock()
+	return fake.registerCallArgsForCall[i].pos
+}
+
+func (fake *FakeHistory) RegisterCallReturns(result1 parsley.Error) {
+	fake.RegisterCallStub = nil
+	fake.registerCallReturns = struct {
+		result1 parsley.Error
+	}{result1}
+}
+
+func (fake *FakeHistory) RegisterCallReturnsOnCall(i int, result1 parsley.Error) {
+	fake.RegisterCallStub = nil
+	if fake.registerCallReturnsOnCall == nil {
+		fake.registerCallReturnsOnCall = make(map[int]struct {
+			result1 parsley.Error
+		})
+	}
+	fake.registerCallReturnsOnCall[i] = struct {
+		result1 parsley.Error
+	}{result1}
+}
+
 func (fake *FakeHistory) CallCount() int {
 	fake.callCountMutex.Lock()
 	ret, specificReturn := fake.callCountReturnsOnCall[len(fake.callCountArgsForCall)]
@@ -187,6 +271,70 @@ func (fake *FakeHistory) CallCountReturnsOnCall(i int, result1 int) {
 	}{result1}
 }
 
+func (fake *FakeHistory) Enter(pos parsley.Pos) parsley.Error {
+	fake.enterMutex.Lock()
+	ret, specificReturn := fake.enterReturnsOnCall[len(fake.enterArgsForCall)]
+	fake.enterArgsForCall = append(fake.enterArgsForCall, struct {
+		pos parsley.Pos
+	}{pos})
+	fake.recordInvocation("Enter", []interface{}{pos})
+	fake.enterMutex.Unlock()
+	if fake.EnterStub != nil {
+		return fake.EnterStub(pos)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.enterReturns.result1
+}
+
+func (fake *FakeHistory) EnterCallCount() int {
+	fake.enterMutex.RLock()
+	defer fake.enterMutex.RUnlock()
+	return len(fake.enterArgsForCall)
+}
+
+func (fake *FakeHistory) EnterArgsForCall(i int) parsley.Pos {
+	fake.enterMutex.RLock()
+	defer fake.enterMutex.RUnlock()
+	return fake.enterArgsForCall[i].pos
+}
+
+func (fake *FakeHistory) EnterReturns(result1 parsley.Error) {
+	fake.EnterStub = nil
+	fake.enterReturns = struct {
+		result1 parsley.Error
+	}{result1}
+}
+
+func (fake *FakeHistory) EnterReturnsOnCall(i int, result1 parsley.Error) {
+	fake.EnterStub = nil
+	if fake.enterReturnsOnCall == nil {
+		fake.enterReturnsOnCall = make(map[int]struct {
+			result1 parsley.Error
+		})
+	}
+	fake.enterReturnsOnCall[i] = struct {
+		result1 parsley.Error
+	}{result1}
+}
+
+func (fake *FakeHistory) Leave() {
+	fake.leaveMutex.Lock()
+	fake.leaveArgsForCall = append(fake.leaveArgsForCall, struct{}{})
+	fake.recordInvocation("Leave", []interface{}{})
+	fake.leaveMutex.Unlock()
+	if fake.LeaveStub != nil {
+		fake.LeaveStub()
+	}
+}
+
+func (fake *FakeHistory) LeaveCallCount() int {
+	fake.leaveMutex.RLock()
+	defer fake.leaveMutex.RUnlock()
+	return len(fake.leaveArgsForCall)
+}
+
 func (fake *FakeHistory) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -198,6 +346,10 @@ func (fake *FakeHistory) Invocations() map[string][][]interface{} {
 	defer fake.registerCallMutex.RUnlock()
 	fake.callCountMutex.RLock()
 	defer fake.callCountMutex.RUnlock()
+	fake.enterMutex.RLock()
+	defer fake.enterMutex.RUnlock()
+	fake.leaveMutex.RLock()
+	defer fake.leaveMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
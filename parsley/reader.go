@@ -18,3 +18,15 @@ type Reader interface {
 	Remaining(Pos) int
 	IsEOF(Pos) bool
 }
+
+// Describer is an optional interface for readers to describe the input at the given position in error messages
+// (e.g. the next character or token)
+type Describer interface {
+	Describe(Pos) string
+}
+
+// Advancer is an optional interface for readers to return with the position of the next input unit (e.g. the next
+// character or token). It's used when the input is skipped, e.g. by combinator.Recover.
+type Advancer interface {
+	Advance(Pos) Pos
+}
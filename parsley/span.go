@@ -0,0 +1,88 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parsley
+
+import (
+	"fmt"
+)
+
+// Span is a range in the input between two positions
+// The end position is exclusive, it points to the first character after the range.
+type Span struct {
+	Start Pos
+	End   Pos
+}
+
+// NewSpan creates a new span
+func NewSpan(start Pos, end Pos) Span {
+	return Span{Start: start, End: end}
+}
+
+// Len returns with the length of the span
+func (s Span) Len() int {
+	return int(s.End - s.Start)
+}
+
+// Contains returns true if the position is inside the span
+func (s Span) Contains(pos Pos) bool {
+	return pos >= s.Start && pos < s.End
+}
+
+// String returns with a string representation of the span
+func (s Span) String() string {
+	return fmt.Sprintf("%d..%d", s.Start, s.End)
+}
+
+// ErrorSpan returns with the span of the error
+// If the error has no range then the span will be empty and start at the error's position.
+func ErrorSpan(e Error) Span {
+	end := EndPos(e)
+	if end < e.Pos() {
+		end = e.Pos()
+	}
+	return Span{Start: e.Pos(), End: end}
+}
+
+// LineColumnPosition is an optional interface for positions which have a line and a column number
+type LineColumnPosition interface {
+	Position
+	LineColumn() (int, int)
+}
+
+// SpanPosition is a span resolved to file positions
+// The end position points to the first character after the range.
+type SpanPosition struct {
+	Start Position
+	End   Position
+}
+
+// String returns with a human-readable representation of the span position
+// If the positions have line and column numbers the result will be in the "file:line:col-line:col" format.
+func (sp SpanPosition) String() string {
+	if sp.End == nil || sp.End == NilPosition || sp.End == sp.Start {
+		return sp.Start.String()
+	}
+	if end, ok := sp.End.(LineColumnPosition); ok {
+		line, col := end.LineColumn()
+		return fmt.Sprintf("%s-%d:%d", sp.Start.String(), line, col)
+	}
+	return fmt.Sprintf("%s-%s", sp.Start.String(), sp.End.String())
+}
+
+// SpanPosition resolves the given span to file positions
+// If the start position is invalid then both the start and end positions will be NilPosition.
+// For an empty span the end position will be the same as the start position.
+func (fs *FileSet) SpanPosition(s Span) SpanPosition {
+	start := fs.Position(s.Start)
+	if start == NilPosition {
+		return SpanPosition{Start: NilPosition, End: NilPosition}
+	}
+	end := start
+	if s.End > s.Start {
+		end = fs.Position(s.End)
+	}
+	return SpanPosition{Start: start, End: end}
+}
@@ -0,0 +1,96 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package parsley_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/parsley/parsleyfakes"
+	"github.com/sniperkit/snk.fork.parsley/text"
+)
+
+var _ = Describe("Span", func() {
+	var span parsley.Span
+
+	BeforeEach(func() {
+		span = parsley.NewSpan(parsley.Pos(2), parsley.Pos(5))
+	})
+
+	It("returns with the length", func() {
+		Expect(span.Len()).To(Equal(3))
+	})
+
+	It("checks whether it contains a position", func() {
+		Expect(span.Contains(parsley.Pos(1))).To(BeFalse())
+		Expect(span.Contains(parsley.Pos(2))).To(BeTrue())
+		Expect(span.Contains(parsley.Pos(4))).To(BeTrue())
+		Expect(span.Contains(parsley.Pos(5))).To(BeFalse())
+	})
+
+	It("returns with a string representation", func() {
+		Expect(span.String()).To(Equal("2..5"))
+	})
+})
+
+var _ = Describe("ErrorSpan", func() {
+	It("returns with the range of the error", func() {
+		err := parsley.NewRangeError(parsley.Pos(2), parsley.Pos(5), errors.New("some error"))
+		Expect(parsley.ErrorSpan(err)).To(Equal(parsley.NewSpan(parsley.Pos(2), parsley.Pos(5))))
+	})
+
+	It("returns with an empty span if the error has no range", func() {
+		err := parsley.NewErrorf(parsley.Pos(2), "some error")
+		Expect(parsley.ErrorSpan(err)).To(Equal(parsley.NewSpan(parsley.Pos(2), parsley.Pos(2))))
+	})
+})
+
+var _ = Describe("SpanPosition", func() {
+	var (
+		fs *parsley.FileSet
+		f  *text.File
+	)
+
+	BeforeEach(func() {
+		f = text.NewFile("testfile", []byte("abc\ndef"))
+		fs = parsley.NewFileSet(f)
+	})
+
+	It("resolves the start and end positions", func() {
+		sp := fs.SpanPosition(parsley.NewSpan(f.Pos(1), f.Pos(6)))
+		Expect(sp.Start).To(Equal(text.NewPosition("testfile", 1, 2)))
+		Expect(sp.End).To(Equal(text.NewPosition("testfile", 2, 3)))
+		Expect(sp.String()).To(Equal("testfile:1:2-2:3"))
+	})
+
+	It("resolves the end of the file", func() {
+		sp := fs.SpanPosition(parsley.NewSpan(f.Pos(4), f.Pos(7)))
+		Expect(sp.End).To(Equal(text.NewPosition("testfile", 2, 4)))
+	})
+
+	It("uses the start position for an empty span", func() {
+		sp := fs.SpanPosition(parsley.NewSpan(f.Pos(1), f.Pos(1)))
+		Expect(sp.End).To(BeIdenticalTo(sp.Start))
+		Expect(sp.String()).To(Equal("testfile:1:2"))
+	})
+
+	It("returns nil positions for an invalid span", func() {
+		sp := fs.SpanPosition(parsley.NewSpan(parsley.NilPos, parsley.NilPos))
+		Expect(sp.Start).To(Equal(parsley.NilPosition))
+		Expect(sp.End).To(Equal(parsley.NilPosition))
+	})
+
+	It("uses the string representation for positions without line and column", func() {
+		start := &parsleyfakes.FakePosition{}
+		start.StringReturns("start")
+		end := &parsleyfakes.FakePosition{}
+		end.StringReturns("end")
+		Expect(parsley.SpanPosition{Start: start, End: end}.String()).To(Equal("start-end"))
+	})
+})
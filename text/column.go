@@ -0,0 +1,58 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Copyright (c) 2017 Opsidian Ltd.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package text
+
+import (
+	"unicode/utf8"
+)
+
+// ColumnMode is a type for defining how the column numbers are calculated
+type ColumnMode uint8
+
+// Column modes
+// ColumnByte means the column is the number of bytes from the line start
+// ColumnRune means the column is the number of unicode characters from the line start
+// ColumnUTF16 means the column is the number of UTF-16 code units from the line start (as used by LSP clients)
+// ColumnVisual means the column is the visual column where tabs are aligned to the tab width
+const (
+	ColumnByte ColumnMode = iota
+	ColumnRune
+	ColumnUTF16
+	ColumnVisual
+)
+
+// DefaultTabWidth is the default tab width for the visual column mode
+const DefaultTabWidth = 4
+
+// column returns with the column number (starting from 1) at the end of the given line prefix
+func (m ColumnMode) column(prefix []byte, tabWidth int) int {
+	switch m {
+	case ColumnRune:
+		return utf8.RuneCount(prefix) + 1
+	case ColumnUTF16:
+		return utf16Len(prefix) + 1
+	case ColumnVisual:
+		col := 0
+		for i := 0; i < len(prefix); {
+			r, size := utf8.DecodeRune(prefix[i:])
+			if r == '\t' {
+				col += tabWidth - col%tabWidth
+			} else {
+				col++
+			}
+			i += size
+		}
+		return col + 1
+	default:
+		return len(prefix) + 1
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package text
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Encoding is the character encoding of the file data
+type Encoding uint8
+
+// Encodings
+// EncodingDetect means the encoding is detected by the byte order mark, UTF-8 is used if there is none
+const (
+	EncodingDetect Encoding = iota
+	EncodingUTF8
+	EncodingUTF16LE
+	EncodingUTF16BE
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case EncodingUTF8:
+		return "UTF-8"
+	case EncodingUTF16LE:
+		return "UTF-16LE"
+	case EncodingUTF16BE:
+		return "UTF-16BE"
+	default:
+		return "detect"
+	}
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// bom returns with the byte order mark of the encoding
+func (e Encoding) bom() []byte {
+	switch e {
+	case EncodingUTF8:
+		return bomUTF8
+	case EncodingUTF16LE:
+		return bomUTF16LE
+	case EncodingUTF16BE:
+		return bomUTF16BE
+	default:
+		return nil
+	}
+}
+
+// FileOptions contains the options for decoding the file data
+type FileOptions struct {
+	// Encoding is the encoding of the data, it's detected by the byte order mark if not set
+	Encoding Encoding
+	// RejectInvalidUTF8 makes the file creation fail with an InvalidUTF8Error if the data is not valid UTF-8
+	RejectInvalidUTF8 bool
+}
+
+// DetectEncoding detects the encoding from the byte order mark
+// It returns with the encoding and the length of the byte order mark. If there is no byte order mark then
+// UTF-8 is returned with zero length.
+func DetectEncoding(data []byte) (Encoding, int) {
+	for _, e := range []Encoding{EncodingUTF8, EncodingUTF16LE, EncodingUTF16BE} {
+		if bytes.HasPrefix(data, e.bom()) {
+			return e, len(e.bom())
+		}
+	}
+	return EncodingUTF8, 0
+}
+
+// InvalidUTF8Error is returned if the file data contains an invalid UTF-8 sequence
+type InvalidUTF8Error struct {
+	// Position is the position of the invalid sequence
+	Position parsley.Position
+	// Offset is the byte offset of the invalid sequence in the original data
+	Offset int
+}
+
+// Error returns with the error message
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("%s: invalid UTF-8 encoding", e.Position)
+}
+
+// decode strips the byte order mark and converts the data to UTF-8
+// It returns with the decoded data, the encoding and the length of the byte order mark.
+func decode(data []byte, encoding Encoding) ([]byte, Encoding, int, error) {
+	bomLen := 0
+	if encoding == EncodingDetect {
+		encoding, bomLen = DetectEncoding(data)
+	} else if bytes.HasPrefix(data, encoding.bom()) {
+		bomLen = len(encoding.bom())
+	}
+
+	switch encoding {
+	case EncodingUTF8:
+		return data[bomLen:], encoding, bomLen, nil
+	case EncodingUTF16LE, EncodingUTF16BE:
+		body := data[bomLen:]
+		if len(body)%2 != 0 {
+			return nil, encoding, bomLen, errors.New("invalid UTF-16 encoding, the data has an odd number of bytes")
+		}
+		units := make([]uint16, len(body)/2)
+		for i := range units {
+			if encoding == EncodingUTF16LE {
+				units[i] = uint16(body[2*i]) | uint16(body[2*i+1])<<8
+			} else {
+				units[i] = uint16(body[2*i])<<8 | uint16(body[2*i+1])
+			}
+		}
+		res := make([]byte, 0, len(body))
+		buf := make([]byte, utf8.UTFMax)
+		for _, r := range utf16.Decode(units) {
+			n := utf8.EncodeRune(buf, r)
+			res = append(res, buf[:n]...)
+		}
+		return res, encoding, bomLen, nil
+	default:
+		return nil, encoding, bomLen, fmt.Errorf("unknown encoding: %d", encoding)
+	}
+}
+
+// utf16Len returns with the number of UTF-16 code units needed to encode the given UTF-8 data
+func utf16Len(data []byte) int {
+	n := 0
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+		i += size
+	}
+	return n
+}
@@ -12,51 +12,121 @@ Sniperkit-Bot
 package text
 
 import (
-	"bytes"
 	"fmt"
 	"io/ioutil"
 	"sort"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/sniperkit/snk.fork.parsley/parsley"
 )
 
 // File contains the contents of a file and the line offsets for quick line+column lookup
+// The file is safe for concurrent use after it was added to a file set and its settings (e.g. the column mode) were
+// set. The line offsets are calculated only once when they are first needed.
 type File struct {
-	filename string
-	data     []byte
-	lines    []int
-	len      int
-	offset   int
+	filename   string
+	data       []byte
+	lines      []int
+	srcLines   []int
+	linesOnce  sync.Once
+	len        int
+	offset     int
+	columnMode ColumnMode
+	tabWidth   int
+	encoding   Encoding
+	bomLen     int
 }
 
 // NewFile creates a new file object
+// The columns in the positions are calculated in bytes by default, see SetColumnMode.
 func NewFile(filename string, data []byte) *File {
 	f := &File{
 		filename: filename,
-		data:     bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1),
+		data:     data,
 		offset:   1,
+		tabWidth: DefaultTabWidth,
+		encoding: EncodingUTF8,
 	}
 	f.len = len(f.data)
 	return f
 }
 
+// NewFileWithOptions creates a new file object by decoding the given data
+// The byte order mark is removed and UTF-16 data is converted to UTF-8. The positions will still refer to the
+// original data, see SourceOffset, but the byte order mark is not counted in the columns of the first line.
+func NewFileWithOptions(filename string, data []byte, opts FileOptions) (*File, error) {
+	decoded, encoding, bomLen, err := decode(data, opts.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("can not decode %s: %s", filename, err)
+	}
+	f := NewFile(filename, decoded)
+	f.encoding = encoding
+	f.bomLen = bomLen
+
+	if opts.RejectInvalidUTF8 && encoding == EncodingUTF8 && !utf8.Valid(decoded) {
+		offset := 0
+		for offset < len(decoded) {
+			r, size := utf8.DecodeRune(decoded[offset:])
+			if r == utf8.RuneError && size == 1 {
+				break
+			}
+			offset += size
+		}
+		return nil, &InvalidUTF8Error{Position: f.Position(offset), Offset: f.SourceOffset(offset)}
+	}
+
+	return f, nil
+}
+
 // ReadFile reads a file and creates a File object
+// The encoding is detected by the byte order mark, see NewFileWithOptions.
 func ReadFile(filename string) (*File, error) {
+	return ReadFileWithOptions(filename, FileOptions{})
+}
+
+// ReadFileWithOptions reads a file and creates a File object using the given options
+func ReadFileWithOptions(filename string, opts FileOptions) (*File, error) {
 	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("can not read %s", filename)
 	}
-	return NewFile(filename, data), nil
+	return NewFileWithOptions(filename, data, opts)
 }
 
-// SetLinesForContent sets the line offsets
+// setLines sets the line offsets
+// The Unix (\n), Windows (\r\n) and the old Mac-style (\r) line endings are all handled as new lines.
+// For UTF-16 data the line offsets in the original data are also calculated, see SourceOffset.
 func (f *File) setLines() {
 	f.lines = []int{0}
-	for offset, b := range f.data {
-		if b == '\n' {
+	for offset := 0; offset < f.len; offset++ {
+		switch f.data[offset] {
+		case '\n':
+			f.lines = append(f.lines, offset+1)
+		case '\r':
+			if offset+1 < f.len && f.data[offset+1] == '\n' {
+				offset++
+			}
 			f.lines = append(f.lines, offset+1)
 		}
 	}
+
+	if f.isUTF16() {
+		f.srcLines = make([]int, len(f.lines))
+		f.srcLines[0] = f.bomLen
+		for i := 1; i < len(f.lines); i++ {
+			f.srcLines[i] = f.srcLines[i-1] + 2*utf16Len(f.data[f.lines[i-1]:f.lines[i]])
+		}
+	}
+}
+
+func (f *File) isUTF16() bool {
+	return f.encoding == EncodingUTF16LE || f.encoding == EncodingUTF16BE
+}
+
+// Filename returns with the name of the file
+func (f *File) Filename() string {
+	return f.filename
 }
 
 // Len returns with the length of the file in bytes
@@ -69,20 +139,89 @@ func (f *File) SetOffset(offset int) {
 	f.offset = offset
 }
 
-// Position returns with a Position object for the given offset
+// SetColumnMode sets how the columns are calculated in the positions
+func (f *File) SetColumnMode(mode ColumnMode) {
+	f.columnMode = mode
+}
+
+// SetTabWidth sets the tab width for the visual column mode
+func (f *File) SetTabWidth(tabWidth int) {
+	if tabWidth <= 0 {
+		panic("tab width should be a positive number")
+	}
+	f.tabWidth = tabWidth
+}
+
+// Encoding returns with the encoding of the original data
+func (f *File) Encoding() Encoding {
+	return f.encoding
+}
+
+// SourceOffset returns with the byte offset in the original data for the given offset
+// The offsets used by the file (e.g. in Pos, LineNumber and Line) are in the decoded data without the byte order mark,
+// so it's different from the offset only if the original data had a byte order mark or it was not UTF-8 encoded.
+func (f *File) SourceOffset(pos int) int {
+	if f.isUTF16() {
+		line := f.LineNumber(pos)
+		return f.srcLines[line-1] + 2*utf16Len(f.data[f.lines[line-1]:pos])
+	}
+	return f.bomLen + pos
+}
+
+// Position returns with a Position object for the given offset using the file's column mode
 func (f *File) Position(pos int) parsley.Position {
+	return f.PositionWithColumnMode(pos, f.columnMode)
+}
+
+// PositionWithColumnMode returns with a Position object for the given offset using the given column mode
+func (f *File) PositionWithColumnMode(pos int, mode ColumnMode) parsley.Position {
 	if pos > f.len {
 		return parsley.NilPosition
 	}
-	if f.lines == nil {
-		f.setLines()
+	line := f.LineNumber(pos)
+	column := mode.column(f.data[f.lines[line-1]:pos], f.tabWidth)
+	if mode == ColumnByte && f.isUTF16() {
+		// the byte columns are counted in the original data, the byte order mark is not counted on the first line
+		column = 2*utf16Len(f.data[f.lines[line-1]:pos]) + 1
 	}
-	i := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > pos }) - 1
 	return &Position{
 		Filename: f.filename,
-		Line:     i + 1,
-		Column:   pos - f.lines[i] + 1,
+		Line:     line,
+		Column:   column,
+	}
+}
+
+// LineCount returns with the number of lines in the file
+func (f *File) LineCount() int {
+	f.linesOnce.Do(f.setLines)
+	return len(f.lines)
+}
+
+// LineNumber returns with the line number (starting from 1) for the given offset
+func (f *File) LineNumber(pos int) int {
+	f.linesOnce.Do(f.setLines)
+	return sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > pos })
+}
+
+// Line returns with the offset and the contents of the given line (starting from 1) without the line ending
+// If the line doesn't exist it returns with -1 and nil. The offset is in the decoded data, the offset in the original
+// data can be get with SourceOffset.
+func (f *File) Line(line int) (int, []byte) {
+	if line < 1 || line > f.LineCount() {
+		return -1, nil
+	}
+	start := f.lines[line-1]
+	end := f.len
+	if line < len(f.lines) {
+		end = f.lines[line]
+		if end > start && f.data[end-1] == '\n' {
+			end--
+		}
+		if end > start && f.data[end-1] == '\r' {
+			end--
+		}
 	}
+	return start, f.data[start:end]
 }
 
 // Pos returns with a global offset in a file set
@@ -15,8 +15,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
 	"github.com/sniperkit/snk.fork.parsley/parsley"
@@ -44,6 +46,10 @@ var _ = Describe("File", func() {
 		var _ parsley.File = &text.File{}
 	})
 
+	It("should implement the parsley.SourceFile interface", func() {
+		var _ parsley.SourceFile = &text.File{}
+	})
+
 	Describe("Position()", func() {
 		It("should return with the position for an offset", func() {
 			Expect(f.Position(0)).To(Equal(text.NewPosition("testfile", 1, 1)))
@@ -58,6 +64,83 @@ var _ = Describe("File", func() {
 		})
 	})
 
+	Describe("column modes", func() {
+		BeforeEach(func() {
+			data = []byte("x\n\taé🍕b")
+		})
+
+		DescribeTable("Position() with a column mode",
+			func(mode text.ColumnMode, offset int, column int) {
+				f.SetColumnMode(mode)
+				Expect(f.Position(offset)).To(Equal(text.NewPosition("testfile", 2, column)))
+				Expect(f.PositionWithColumnMode(offset, mode)).To(Equal(text.NewPosition("testfile", 2, column)))
+			},
+			Entry("byte: line start", text.ColumnByte, 2, 1),
+			Entry("byte: after tab", text.ColumnByte, 3, 2),
+			Entry("byte: after é", text.ColumnByte, 6, 5),
+			Entry("byte: after 🍕", text.ColumnByte, 10, 9),
+			Entry("rune: after tab", text.ColumnRune, 3, 2),
+			Entry("rune: after é", text.ColumnRune, 6, 4),
+			Entry("rune: after 🍕", text.ColumnRune, 10, 5),
+			Entry("UTF-16: after é", text.ColumnUTF16, 6, 4),
+			Entry("UTF-16: after 🍕", text.ColumnUTF16, 10, 6),
+			Entry("visual: after tab", text.ColumnVisual, 3, 5),
+			Entry("visual: after 🍕", text.ColumnVisual, 10, 8),
+		)
+
+		It("should use bytes by default", func() {
+			Expect(f.Position(6)).To(Equal(text.NewPosition("testfile", 2, 5)))
+		})
+
+		It("should allow to use a different column mode for a lookup", func() {
+			f.SetColumnMode(text.ColumnRune)
+			Expect(f.PositionWithColumnMode(6, text.ColumnByte)).To(Equal(text.NewPosition("testfile", 2, 5)))
+			Expect(f.Position(6)).To(Equal(text.NewPosition("testfile", 2, 4)))
+		})
+
+		It("should use the tab width in visual mode", func() {
+			f.SetTabWidth(8)
+			Expect(f.PositionWithColumnMode(3, text.ColumnVisual)).To(Equal(text.NewPosition("testfile", 2, 9)))
+		})
+
+		It("should panic if the tab width is invalid", func() {
+			Expect(func() { f.SetTabWidth(0) }).To(Panic())
+		})
+	})
+
+	Describe("LineCount()", func() {
+		It("should return with the number of lines", func() {
+			Expect(f.LineCount()).To(Equal(2))
+		})
+	})
+
+	Describe("LineNumber()", func() {
+		It("should return with the line number for an offset", func() {
+			Expect(f.LineNumber(0)).To(Equal(1))
+			Expect(f.LineNumber(2)).To(Equal(1))
+			Expect(f.LineNumber(3)).To(Equal(2))
+			Expect(f.LineNumber(4)).To(Equal(2))
+		})
+	})
+
+	Describe("Line()", func() {
+		It("should return with the offset and the contents of the line", func() {
+			offset, content := f.Line(1)
+			Expect(offset).To(Equal(0))
+			Expect(content).To(Equal([]byte("ab")))
+
+			offset, content = f.Line(2)
+			Expect(offset).To(Equal(3))
+			Expect(content).To(Equal([]byte("c")))
+		})
+
+		It("should return with -1 and nil for an invalid line", func() {
+			offset, content := f.Line(3)
+			Expect(offset).To(Equal(-1))
+			Expect(content).To(BeNil())
+		})
+	})
+
 	Describe("Len()", func() {
 		Context("when data is empty", func() {
 			BeforeEach(func() {
@@ -79,12 +162,39 @@ var _ = Describe("File", func() {
 			data = []byte("a\r\nb\r\nc\n")
 		})
 
-		It("should remove the \r characters", func() {
-			Expect(f.Len()).To(Equal(6))
+		It("should keep the original data", func() {
+			Expect(f.Len()).To(Equal(8))
+		})
+
+		It("should handle \r\n as a single new line", func() {
+			Expect(f.LineCount()).To(Equal(4))
 			Expect(f.Position(1).(*text.Position).Line).To(Equal(1))
-			Expect(f.Position(2).(*text.Position).Line).To(Equal(2))
+			Expect(f.Position(2).(*text.Position).Line).To(Equal(1))
 			Expect(f.Position(3).(*text.Position).Line).To(Equal(2))
-			Expect(f.Position(4).(*text.Position).Line).To(Equal(3))
+			Expect(f.Position(6).(*text.Position).Line).To(Equal(3))
+			Expect(f.Position(6).(*text.Position).Column).To(Equal(1))
+		})
+
+		It("should not return the line endings as part of the line", func() {
+			offset, content := f.Line(2)
+			Expect(offset).To(Equal(3))
+			Expect(content).To(Equal([]byte("b")))
+		})
+	})
+
+	Context("when data contains old Mac-style line endings", func() {
+		BeforeEach(func() {
+			data = []byte("a\rb\r\rc")
+		})
+
+		It("should handle \r as a new line", func() {
+			Expect(f.LineCount()).To(Equal(4))
+			Expect(f.Position(2).(*text.Position).Line).To(Equal(2))
+			Expect(f.Position(5).(*text.Position).Line).To(Equal(4))
+
+			offset, content := f.Line(3)
+			Expect(offset).To(Equal(4))
+			Expect(content).To(BeEmpty())
 		})
 	})
 
@@ -145,6 +255,41 @@ var _ = Describe("File", func() {
 			})
 		})
 
+		Context("reading a file with a byte order mark", func() {
+			var tmpDir string
+
+			BeforeEach(func() {
+				var err error
+				tmpDir, err = ioutil.TempDir("", "parsley-test-")
+				Expect(err).ToNot(HaveOccurred())
+				filename = filepath.Join(tmpDir, "testfile")
+				ioutil.WriteFile(filename, []byte("\xEF\xBB\xBFab\nc"), 0600)
+			})
+
+			AfterEach(func() {
+				if tmpDir != "" {
+					os.RemoveAll(tmpDir)
+				}
+			})
+
+			It("should remove the byte order mark", func() {
+				Expect(readFileErr).ToNot(HaveOccurred())
+				Expect(f.Len()).To(Equal(4))
+				Expect(f.Encoding()).To(Equal(text.EncodingUTF8))
+				_, content := f.Line(1)
+				Expect(content).To(Equal([]byte("ab")))
+			})
+
+			It("should return with offsets relative to the file", func() {
+				Expect(readFileErr).ToNot(HaveOccurred())
+				offset, content := f.Line(2)
+				Expect(offset).To(Equal(3))
+				Expect(content).To(Equal([]byte("c")))
+				Expect(f.SourceOffset(offset)).To(Equal(6))
+				Expect(f.Position(offset)).To(Equal(text.NewPosition(filename, 2, 1)))
+			})
+		})
+
 		Context("reading a non-existing file", func() {
 			BeforeEach(func() {
 				filename = "/tmp/non-existing-filename"
@@ -156,6 +301,162 @@ var _ = Describe("File", func() {
 		})
 	})
 
+	DescribeTable("DetectEncoding()",
+		func(data string, expectedEncoding text.Encoding, expectedBOMLen int) {
+			encoding, bomLen := text.DetectEncoding([]byte(data))
+			Expect(encoding).To(Equal(expectedEncoding))
+			Expect(bomLen).To(Equal(expectedBOMLen))
+		},
+		Entry("no byte order mark", "abc", text.EncodingUTF8, 0),
+		Entry("empty", "", text.EncodingUTF8, 0),
+		Entry("UTF-8", "\xEF\xBB\xBFabc", text.EncodingUTF8, 3),
+		Entry("UTF-16LE", "\xFF\xFEa\x00", text.EncodingUTF16LE, 2),
+		Entry("UTF-16BE", "\xFE\xFF\x00a", text.EncodingUTF16BE, 2),
+	)
+
+	Describe("NewFileWithOptions()", func() {
+		var (
+			opts    text.FileOptions
+			fileErr error
+		)
+
+		BeforeEach(func() {
+			opts = text.FileOptions{}
+		})
+
+		JustBeforeEach(func() {
+			f, fileErr = text.NewFileWithOptions("testfile", data, opts)
+		})
+
+		Context("when the data has an UTF-8 byte order mark", func() {
+			BeforeEach(func() {
+				data = []byte("\xEF\xBB\xBFab\nc")
+			})
+
+			It("should remove the byte order mark", func() {
+				Expect(fileErr).ToNot(HaveOccurred())
+				Expect(f.Len()).To(Equal(4))
+				Expect(f.Encoding()).To(Equal(text.EncodingUTF8))
+			})
+
+			It("should return with positions relative to the original data", func() {
+				Expect(f.SourceOffset(0)).To(Equal(3))
+				Expect(f.SourceOffset(3)).To(Equal(6))
+				Expect(f.Position(1)).To(Equal(text.NewPosition("testfile", 1, 2)))
+				Expect(f.Position(3)).To(Equal(text.NewPosition("testfile", 2, 1)))
+				Expect(f.PositionWithColumnMode(1, text.ColumnRune)).To(Equal(text.NewPosition("testfile", 1, 2)))
+			})
+		})
+
+		Context("when the data has an UTF-8 byte order mark and CRLF line endings", func() {
+			BeforeEach(func() {
+				data = []byte("\xEF\xBB\xBFab\r\ncd\r\ne")
+			})
+
+			It("should map the line offsets to the original data", func() {
+				Expect(fileErr).ToNot(HaveOccurred())
+				Expect(f.LineCount()).To(Equal(3))
+				for line, expected := range []struct {
+					content      string
+					sourceOffset int
+				}{{"ab", 3}, {"cd", 7}, {"e", 11}} {
+					offset, content := f.Line(line + 1)
+					Expect(string(content)).To(Equal(expected.content))
+					Expect(f.SourceOffset(offset)).To(Equal(expected.sourceOffset))
+					Expect(data[f.SourceOffset(offset)]).To(Equal(expected.content[0]))
+					Expect(f.LineNumber(offset)).To(Equal(line + 1))
+				}
+				Expect(f.Position(5)).To(Equal(text.NewPosition("testfile", 2, 2)))
+			})
+		})
+
+		Context("when the data is UTF-16LE encoded", func() {
+			BeforeEach(func() {
+				data = []byte("\xFF\xFEa\x00\xE9\x00\n\x00\x3C\xD8\x55\xDFb\x00")
+			})
+
+			It("should convert the data to UTF-8", func() {
+				Expect(fileErr).ToNot(HaveOccurred())
+				Expect(f.Encoding()).To(Equal(text.EncodingUTF16LE))
+				_, content := f.Line(1)
+				Expect(string(content)).To(Equal("aé"))
+				_, content = f.Line(2)
+				Expect(string(content)).To(Equal("🍕b"))
+			})
+
+			It("should return with positions relative to the original data", func() {
+				Expect(f.SourceOffset(1)).To(Equal(4))
+				Expect(f.SourceOffset(4)).To(Equal(8))
+				Expect(f.SourceOffset(8)).To(Equal(12))
+				Expect(f.Position(1)).To(Equal(text.NewPosition("testfile", 1, 3)))
+				Expect(f.Position(8)).To(Equal(text.NewPosition("testfile", 2, 5)))
+				Expect(f.SourceOffset(f.Len())).To(Equal(len(data)))
+				Expect(f.PositionWithColumnMode(8, text.ColumnRune)).To(Equal(text.NewPosition("testfile", 2, 2)))
+			})
+		})
+
+		Context("when the data is UTF-16BE encoded", func() {
+			BeforeEach(func() {
+				data = []byte("\xFE\xFF\x00a\x00b")
+			})
+
+			It("should convert the data to UTF-8", func() {
+				Expect(fileErr).ToNot(HaveOccurred())
+				Expect(f.Encoding()).To(Equal(text.EncodingUTF16BE))
+				_, content := f.Line(1)
+				Expect(string(content)).To(Equal("ab"))
+			})
+		})
+
+		Context("when the encoding is set without a byte order mark", func() {
+			BeforeEach(func() {
+				data = []byte("a\x00b\x00")
+				opts.Encoding = text.EncodingUTF16LE
+			})
+
+			It("should use the given encoding", func() {
+				Expect(fileErr).ToNot(HaveOccurred())
+				_, content := f.Line(1)
+				Expect(string(content)).To(Equal("ab"))
+				Expect(f.SourceOffset(1)).To(Equal(2))
+			})
+		})
+
+		Context("when the UTF-16 data has an odd number of bytes", func() {
+			BeforeEach(func() {
+				data = []byte("\xFF\xFEa\x00b")
+			})
+
+			It("should return an error", func() {
+				Expect(fileErr).To(MatchError("can not decode testfile: invalid UTF-16 encoding, the data has an odd number of bytes"))
+			})
+		})
+
+		Context("when the data contains invalid UTF-8", func() {
+			BeforeEach(func() {
+				data = []byte("\xEF\xBB\xBFab\nc\xFFd")
+			})
+
+			It("should keep the data by default", func() {
+				Expect(fileErr).ToNot(HaveOccurred())
+				Expect(f.Len()).To(Equal(6))
+			})
+
+			Context("when invalid UTF-8 is rejected", func() {
+				BeforeEach(func() {
+					opts.RejectInvalidUTF8 = true
+				})
+
+				It("should return a positioned error", func() {
+					Expect(fileErr).To(MatchError("testfile:2:2: invalid UTF-8 encoding"))
+					invalidErr, ok := fileErr.(*text.InvalidUTF8Error)
+					Expect(ok).To(BeTrue())
+					Expect(invalidErr.Offset).To(Equal(7))
+				})
+			})
+		})
+	})
+
 	Describe("Pos()", func() {
 		It("should return with a global position (pos 0)", func() {
 			pos := f.Pos(0)
@@ -170,3 +471,21 @@ var _ = Describe("File", func() {
 		})
 	})
 })
+
+var _ = Describe("File used concurrently", func() {
+	It("should calculate the lines only once", func() {
+		f := text.NewFile("testfile", []byte("a\nbc\n\ndef"))
+		var wg sync.WaitGroup
+		lines := make([]int, 8)
+		for i := range lines {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				lines[i] = f.LineNumber(9)
+			}(i)
+		}
+		wg.Wait()
+		Expect(lines).To(ConsistOf(4, 4, 4, 4, 4, 4, 4, 4))
+		Expect(f.LineCount()).To(Equal(4))
+	})
+})
@@ -13,28 +13,43 @@ import (
 )
 
 // LeftTrim skips the whitespaces before it tries to match the given parser
+// If the parser doesn't match then the expected error will point after the whitespaces.
 func LeftTrim(p parsley.Parser, wsMode WsMode) *parser.NamedFunc {
 	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
-		return p.Parse(h, leftRecCtx, r, r.(*Reader).SkipWhitespaces(pos, wsMode))
-	}).WithName(p.Name)
+		pos = r.(*Reader).SkipWhitespaces(pos, wsMode)
+		res, err, cp := parsley.Call(h, p, leftRecCtx, r, pos)
+		if res == nil && err == nil {
+			if name := p.Name(); name != "" {
+				err = parsley.NewExpectedError(pos, name)
+			}
+		}
+		return res, err, cp
+	}).WithName(p.Name).WithChildren(parsley.KindWrapper, p)
 }
 
 // RightTrim reads and skips the whitespaces after any parser matches and updates the reader position
 func RightTrim(p parsley.Parser, wsMode WsMode) *parser.NamedFunc {
 	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
 		tr := r.(*Reader)
-		res, err, cp := p.Parse(h, leftRecCtx, r, pos)
+		res, err, cp := parsley.Call(h, p, leftRecCtx, r, pos)
 		if res != nil {
 			res = ast.SetReaderPos(res, func(pos parsley.Pos) parsley.Pos { return tr.SkipWhitespaces(pos, wsMode) })
 		}
-		if err != nil {
+		if err != nil && !parsley.IsFatal(err) {
 			errPos := tr.SkipWhitespaces(err.Pos(), wsMode)
 			if errPos > err.Pos() {
-				err = parsley.NewErrorf(errPos, err.Error())
+				if expectedErr, ok := err.(*parsley.ExpectedError); ok {
+					// the expected errors are recreated so they can still be merged with other expected errors
+					movedErr := parsley.NewExpectedError(errPos, expectedErr.Expected...)
+					movedErr.Found = expectedErr.Found
+					err = movedErr
+				} else {
+					err = parsley.MoveError(err, errPos)
+				}
 			}
 		}
 		return res, err, cp
-	}).WithName(p.Name)
+	}).WithName(p.Name).WithChildren(parsley.KindWrapper, p)
 }
 
 // Trim removes all whitespaces before and after the result token
@@ -6,6 +6,10 @@ Sniperkit-Bot
 package text_test
 
 import (
+	"errors"
+	"fmt"
+	"io"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -109,6 +113,20 @@ var _ = Describe("Trim parsers", func() {
 				Expect(passedPos).To(Equal(pos + 4))
 			})
 		})
+
+		Context("when the parser doesn't match", func() {
+			BeforeEach(func() {
+				wsMode = text.WsSpaces
+				parserRes = nil
+				parserErr = nil
+				fakep.NameReturns("p")
+			})
+
+			It("should return an expected error after the whitespaces", func() {
+				Expect(res).To(BeNil())
+				Expect(err).To(Equal(parsley.NewExpectedError(pos+2, "p")))
+			})
+		})
 	})
 
 	var _ = Describe("RightTrim", func() {
@@ -208,6 +226,69 @@ var _ = Describe("Trim parsers", func() {
 					Expect(err.Pos()).To(Equal(parsley.Pos(8)))
 				})
 			})
+
+			Context("when the error is an expected error", func() {
+				BeforeEach(func() {
+					wsMode = text.WsSpaces
+					parserErr = parsley.NewExpectedError(parsley.Pos(4), "a", "b")
+				})
+
+				It("should keep the expected parsers", func() {
+					Expect(err).To(Equal(parsley.NewExpectedError(parsley.Pos(6), "a", "b")))
+				})
+
+				Context("when the input was described", func() {
+					BeforeEach(func() {
+						expectedErr := parsley.NewExpectedError(parsley.Pos(4), "a", "b")
+						expectedErr.Found = `"x"`
+						parserErr = expectedErr
+					})
+
+					It("should keep the description", func() {
+						Expect(err.Pos()).To(Equal(parsley.Pos(6)))
+						Expect(err).To(MatchError(`was expecting a or b, found "x"`))
+					})
+				})
+			})
+
+			Context("when the error is a typed error", func() {
+				BeforeEach(func() {
+					wsMode = text.WsSpaces
+					parserErr = parsley.NewUnexpectedEOFError(parsley.Pos(4), "a")
+				})
+
+				It("should keep the original error in the error chain", func() {
+					Expect(err.Pos()).To(Equal(parsley.Pos(6)))
+					Expect(errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue())
+					var eofErr *parsley.UnexpectedEOFError
+					Expect(errors.As(err, &eofErr)).To(BeTrue())
+					Expect(eofErr).To(BeIdenticalTo(parserErr))
+				})
+			})
+
+			Context("when the error is an invalid value error", func() {
+				BeforeEach(func() {
+					wsMode = text.WsSpaces
+					parserErr = parsley.NewInvalidValueError(parsley.Pos(2), parsley.Pos(4), "integer", "bc", nil)
+				})
+
+				It("should keep the end position", func() {
+					Expect(err.Pos()).To(Equal(parsley.Pos(2)))
+					Expect(parsley.EndPos(err)).To(Equal(parsley.Pos(4)))
+				})
+			})
+
+			Context("when the error is fatal", func() {
+				BeforeEach(func() {
+					wsMode = text.WsSpaces
+					parserErr = parsley.NewFatalError(parsley.Pos(4), fmt.Errorf("fatal"))
+				})
+
+				It("should keep the error fatal", func() {
+					Expect(parsley.IsFatal(err)).To(BeTrue())
+					Expect(err).To(BeIdenticalTo(parserErr))
+				})
+			})
 		})
 	})
 
@@ -38,3 +38,8 @@ func (pos Position) String() string {
 
 	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
 }
+
+// LineColumn returns with the line and column number
+func (pos Position) LineColumn() (int, int) {
+	return pos.Line, pos.Column
+}
@@ -40,6 +40,16 @@ var _ = Describe("Position", func() {
 		var _ parsley.Position = text.Position{}
 	})
 
+	It("should implement the parsley.LineColumnPosition interface", func() {
+		var _ parsley.LineColumnPosition = text.Position{}
+	})
+
+	It("should return with the line and column", func() {
+		l, c := pos.LineColumn()
+		Expect(l).To(Equal(1))
+		Expect(c).To(Equal(2))
+	})
+
 	It("should return with a string containing all information", func() {
 		Expect(pos.String()).To(Equal("testfile:1:2"))
 	})
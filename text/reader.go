@@ -15,6 +15,8 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"strconv"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/sniperkit/snk.fork.parsley/parsley"
@@ -35,13 +37,15 @@ const (
 
 // Reader defines a text input reader
 // For more efficient reading it provides methods for regexp matching.
+// A reader should be used only by one parsing at a time, but multiple readers can be used concurrently even for
+// the same file.
 type Reader struct {
 	file        *File
+	regexpMu    sync.Mutex
 	regexpCache map[string]*regexp.Regexp
 }
 
 // NewReader creates a new reader instance
-// The Windows-style line endings (\r\n) are automatically replaced with Unix-style line endings (\n).
 func NewReader(file *File) *Reader {
 	return &Reader{
 		file:        file,
@@ -118,14 +122,31 @@ func (r *Reader) MatchWord(pos parsley.Pos, word string) (parsley.Pos, bool) {
 
 // ReadRegexp matches part of the input based on the given regular expression
 // and returns with the full match
+// The expression is compiled only once per reader, but it panics if the expression is invalid. In parsers you should
+// compile it in advance with CompileRegexp and use ReadCompiledRegexp.
 func (r *Reader) ReadRegexp(pos parsley.Pos, expr string) (parsley.Pos, []byte) {
+	return r.ReadCompiledRegexp(pos, r.getPattern(expr))
+}
+
+// ReadRegexpSubmatch matches part of the input based on the given regular expression
+// and returns with all capturing groups
+// The expression is compiled only once per reader, but it panics if the expression is invalid. In parsers you should
+// compile it in advance with CompileRegexp and use ReadCompiledRegexpSubmatch.
+func (r *Reader) ReadRegexpSubmatch(pos parsley.Pos, expr string) (parsley.Pos, [][]byte) {
+	return r.ReadCompiledRegexpSubmatch(pos, r.getPattern(expr))
+}
+
+// ReadCompiledRegexp matches part of the input based on the given compiled regular expression
+// and returns with the full match
+// The regular expression must be created with CompileRegexp or MustCompileRegexp.
+func (r *Reader) ReadCompiledRegexp(pos parsley.Pos, rc *regexp.Regexp) (parsley.Pos, []byte) {
 	cur := int(pos) - r.file.offset
 
 	if cur >= r.file.len {
 		return pos, nil
 	}
 
-	indices := r.getPattern(expr).FindIndex(r.file.data[cur:])
+	indices := rc.FindIndex(r.file.data[cur:])
 	if indices == nil {
 		return pos, nil
 	}
@@ -133,16 +154,17 @@ func (r *Reader) ReadRegexp(pos parsley.Pos, expr string) (parsley.Pos, []byte)
 	return r.file.Pos(cur + indices[1]), r.file.data[cur : cur+indices[1]]
 }
 
-// ReadRegexpSubmatch matches part of the input based on the given regular expression
+// ReadCompiledRegexpSubmatch matches part of the input based on the given compiled regular expression
 // and returns with all capturing groups
-func (r *Reader) ReadRegexpSubmatch(pos parsley.Pos, expr string) (parsley.Pos, [][]byte) {
+// The regular expression must be created with CompileRegexp or MustCompileRegexp.
+func (r *Reader) ReadCompiledRegexpSubmatch(pos parsley.Pos, rc *regexp.Regexp) (parsley.Pos, [][]byte) {
 	cur := int(pos) - r.file.offset
 
 	if cur >= r.file.len {
 		return pos, nil
 	}
 
-	matches := r.getPattern(expr).FindSubmatch(r.file.data[cur:])
+	matches := rc.FindSubmatch(r.file.data[cur:])
 	if matches == nil {
 		return pos, nil
 	}
@@ -173,6 +195,16 @@ func (r *Reader) Readf(pos parsley.Pos, f func(b []byte) ([]byte, int)) (parsley
 	return r.file.Pos(cur + nextPos), value
 }
 
+// Advance returns with the position of the next character
+func (r *Reader) Advance(pos parsley.Pos) parsley.Pos {
+	cur := int(pos) - r.file.offset
+	if cur >= r.file.len {
+		return pos
+	}
+	_, size := utf8.DecodeRune(r.file.data[cur:])
+	return r.file.Pos(cur + size)
+}
+
 // Remaining returns with the remaining character count
 func (r *Reader) Remaining(pos parsley.Pos) int {
 	return r.file.len - (int(pos) - r.file.offset)
@@ -183,6 +215,15 @@ func (r *Reader) IsEOF(pos parsley.Pos) bool {
 	return int(pos)-r.file.offset >= r.file.len
 }
 
+// Describe returns with the quoted next character or "end of input" if we reached the end of the buffer
+func (r *Reader) Describe(pos parsley.Pos) string {
+	if r.IsEOF(pos) {
+		return "end of input"
+	}
+	ch, _ := utf8.DecodeRune(r.file.data[int(pos)-r.file.offset:])
+	return strconv.Quote(string(ch))
+}
+
 // SkipWhitespaces skips the given whitespaces all the whitespaces
 func (r *Reader) SkipWhitespaces(pos parsley.Pos, wsMode WsMode) parsley.Pos {
 	cur := int(pos) - r.file.offset
@@ -192,7 +233,7 @@ func (r *Reader) SkipWhitespaces(pos parsley.Pos, wsMode WsMode) parsley.Pos {
 			cur++
 		}
 	case WsSpacesNl:
-		for cur < r.file.len && (r.file.data[cur] == '\t' || r.file.data[cur] == '\n' || r.file.data[cur] == '\f' || r.file.data[cur] == ' ') {
+		for cur < r.file.len && (r.file.data[cur] == '\t' || r.file.data[cur] == '\n' || r.file.data[cur] == '\r' || r.file.data[cur] == '\f' || r.file.data[cur] == ' ') {
 			cur++
 		}
 	}
@@ -205,15 +246,34 @@ func (r *Reader) Pos(cur int) parsley.Pos {
 }
 
 func (r *Reader) getPattern(expr string) *regexp.Regexp {
+	r.regexpMu.Lock()
+	defer r.regexpMu.Unlock()
 	rc, ok := r.regexpCache[expr]
 	if !ok {
-		rc = regexp.MustCompile("^(?:" + expr + ")")
+		rc = MustCompileRegexp(expr)
+		r.regexpCache[expr] = rc
+	}
+	return rc
+}
 
-		if rc.Match(nil) {
-			panic(fmt.Errorf("'%s' is not allowed to match an empty input", expr))
-		}
+// CompileRegexp compiles a regular expression for ReadCompiledRegexp and ReadCompiledRegexpSubmatch
+// The expression will only match at the reading position and it's not allowed to match an empty input.
+func CompileRegexp(expr string) (*regexp.Regexp, error) {
+	if _, err := regexp.Compile(expr); err != nil {
+		return nil, err
+	}
+	rc := regexp.MustCompile("^(?:" + expr + ")")
+	if rc.Match(nil) {
+		return nil, fmt.Errorf("'%s' is not allowed to match an empty input", expr)
+	}
+	return rc, nil
+}
 
-		r.regexpCache[expr] = rc
+// MustCompileRegexp is like CompileRegexp but panics if the expression is invalid
+func MustCompileRegexp(expr string) *regexp.Regexp {
+	rc, err := CompileRegexp(expr)
+	if err != nil {
+		panic(err)
 	}
 	return rc
 }
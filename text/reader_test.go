@@ -12,6 +12,9 @@ Sniperkit-Bot
 package text_test
 
 import (
+	"sync"
+	"testing"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -283,6 +286,40 @@ var _ = Describe("Reader", func() {
 		})
 	})
 
+	Describe("ReadCompiledRegexp()", func() {
+		It("should match the regexp", func() {
+			pos, match := r.ReadCompiledRegexp(f.Pos(0), text.MustCompileRegexp("a+b+x?"))
+			Expect(pos).To(Equal(f.Pos(2)))
+			Expect(match).To(Equal([]byte("ab")))
+		})
+
+		It("should only match at the given position", func() {
+			pos, match := r.ReadCompiledRegexp(f.Pos(0), text.MustCompileRegexp("def"))
+			Expect(pos).To(Equal(f.Pos(0)))
+			Expect(match).To(BeNil())
+		})
+
+		It("should not match at the end of the input", func() {
+			pos, match := r.ReadCompiledRegexp(f.Pos(7), text.MustCompileRegexp("x+"))
+			Expect(pos).To(Equal(f.Pos(7)))
+			Expect(match).To(BeNil())
+		})
+	})
+
+	Describe("ReadCompiledRegexpSubmatch()", func() {
+		It("should match the regexp", func() {
+			pos, match := r.ReadCompiledRegexpSubmatch(f.Pos(4), text.MustCompileRegexp("(d)ef"))
+			Expect(pos).To(Equal(f.Pos(7)))
+			Expect(match).To(Equal([][]byte{[]byte("def"), []byte("d")}))
+		})
+
+		It("should not match a different input", func() {
+			pos, match := r.ReadCompiledRegexpSubmatch(f.Pos(0), text.MustCompileRegexp("(d)ef"))
+			Expect(pos).To(Equal(f.Pos(0)))
+			Expect(match).To(BeNil())
+		})
+	})
+
 	Describe("ReadRegexpSubmatch()", func() {
 		Context("when matches an empty string", func() {
 			It("should panic", func() {
@@ -440,6 +477,21 @@ var _ = Describe("Reader", func() {
 		})
 	})
 
+	Describe("Advance()", func() {
+		BeforeEach(func() {
+			data = []byte(inputWithUTF8)
+		})
+
+		It("should return with the position of the next character", func() {
+			Expect(r.Advance(f.Pos(0))).To(Equal(f.Pos(4)))
+			Expect(r.Advance(f.Pos(4))).To(Equal(f.Pos(5)))
+		})
+
+		It("should not move at the end of the input", func() {
+			Expect(r.Advance(f.Pos(len(data)))).To(Equal(f.Pos(len(data))))
+		})
+	})
+
 	Describe("Remaining()", func() {
 		It("should return with the remaining bytes", func() {
 			Expect(r.Remaining(f.Pos(0))).To(Equal(len(input)))
@@ -466,9 +518,25 @@ var _ = Describe("Reader", func() {
 		})
 	})
 
+	Describe("Describe()", func() {
+		BeforeEach(func() {
+			data = []byte("aé\n")
+		})
+
+		It("should return with the next quoted character", func() {
+			Expect(r.Describe(f.Pos(0))).To(Equal(`"a"`))
+			Expect(r.Describe(f.Pos(1))).To(Equal(`"é"`))
+			Expect(r.Describe(f.Pos(3))).To(Equal(`"\n"`))
+		})
+
+		It("should return end of input at the end", func() {
+			Expect(r.Describe(f.Pos(4))).To(Equal("end of input"))
+		})
+	})
+
 	Describe("SkipWhitespaces()", func() {
 		BeforeEach(func() {
-			data = []byte("abc \t\n\fdef")
+			data = []byte("abc \t\r\n\fdef")
 		})
 
 		It("should not match any whitespaces if none", func() {
@@ -478,7 +546,7 @@ var _ = Describe("Reader", func() {
 
 		It("should match all types of whitespaces", func() {
 			pos := r.SkipWhitespaces(f.Pos(3), text.WsSpacesNl)
-			Expect(pos).To(Equal(f.Pos(7)))
+			Expect(pos).To(Equal(f.Pos(8)))
 		})
 
 		Context("when not including new lines", func() {
@@ -496,3 +564,84 @@ var _ = Describe("Reader", func() {
 		})
 	})
 })
+
+var _ = Describe("CompileRegexp", func() {
+	It("should compile a valid regexp", func() {
+		rc, err := text.CompileRegexp("a+")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rc.String()).To(Equal("^(?:a+)"))
+	})
+
+	It("should return an error if the regexp can match an empty input", func() {
+		_, err := text.CompileRegexp("a*")
+		Expect(err).To(MatchError("'a*' is not allowed to match an empty input"))
+	})
+
+	It("should return an error if the regexp is invalid", func() {
+		_, err := text.CompileRegexp("[a-")
+		Expect(err).To(MatchError("error parsing regexp: missing closing ]: `[a-`"))
+	})
+
+	It("should panic in MustCompileRegexp if the regexp is invalid", func() {
+		Expect(func() { text.MustCompileRegexp("a*") }).To(Panic())
+	})
+})
+
+var _ = Describe("Reader used concurrently", func() {
+	It("should allow to use the same compiled regexp and file in several goroutines", func() {
+		f := text.NewFile("testfile", []byte("abc def"))
+		rc := text.MustCompileRegexp("[a-z]+ concurrent|[a-z]+")
+		var wg sync.WaitGroup
+		matches := make([]string, 8)
+		for i := range matches {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, match := text.NewReader(f).ReadCompiledRegexp(f.Pos(0), rc)
+				matches[i] = string(match)
+			}(i)
+		}
+		wg.Wait()
+		for _, match := range matches {
+			Expect(match).To(Equal("abc"))
+		}
+	})
+
+	It("should allow to use the regexp cache of the same reader in several goroutines", func() {
+		f := text.NewFile("testfile", []byte("abc def"))
+		r := text.NewReader(f)
+		var wg sync.WaitGroup
+		matches := make([]string, 8)
+		for i := range matches {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, match := r.ReadRegexp(f.Pos(0), "[a-z]+ concurrent|[a-z]+")
+				matches[i] = string(match)
+			}(i)
+		}
+		wg.Wait()
+		for _, match := range matches {
+			Expect(match).To(Equal("abc"))
+		}
+	})
+})
+
+func BenchmarkReaderReadRegexp(b *testing.B) {
+	f := text.NewFile("testfile", []byte("abc def"))
+	r := text.NewReader(f)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.ReadRegexp(f.Pos(0), "[a-z]+")
+	}
+}
+
+func BenchmarkReaderReadCompiledRegexp(b *testing.B) {
+	f := text.NewFile("testfile", []byte("abc def"))
+	r := text.NewReader(f)
+	rc := text.MustCompileRegexp("[a-z]+")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.ReadCompiledRegexp(f.Pos(0), rc)
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/sniperkit/snk.fork.parsley/text"
 )
 
+var charRegexp = text.MustCompileRegexp(`\\[abfnrtv']|\\x[0-9a-fA-F]{2,2}|\\u[0-9a-fA-F]{4,4}|\\U[0-9a-fA-F]{8,8}|[^']`)
+
 // Char matches a character literal enclosed in single quotes
 func Char() *parser.NamedFunc {
 	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
@@ -30,21 +32,19 @@ func Char() *parser.NamedFunc {
 			return nil, nil, data.EmptyIntSet
 		}
 
-		readerPos, res := tr.ReadRegexp(
-			readerPos, `\\[abfnrtv']|\\x[0-9a-fA-F]{2,2}|\\u[0-9a-fA-F]{4,4}|\\U[0-9a-fA-F]{8,8}|[^']`,
-		)
+		readerPos, res := tr.ReadCompiledRegexp(readerPos, charRegexp)
 		if res == nil {
-			return nil, parsley.NewErrorf(readerPos, "was expecting one character"), data.EmptyIntSet
+			return nil, parsley.DescribeError(r, parsley.NewExpectedError(readerPos, "one character")), data.EmptyIntSet
 		}
 
 		readerPos, found = tr.ReadRune(readerPos, '\'')
 		if !found {
-			return nil, parsley.NewErrorf(readerPos, "was expecting \"'\""), data.EmptyIntSet
+			return nil, parsley.DescribeError(r, parsley.NewExpectedError(readerPos, `"'"`)), data.EmptyIntSet
 		}
 
 		value, _, tail, err := strconv.UnquoteChar(string(res), '\'')
 		if tail != "" || err != nil {
-			return nil, parsley.NewErrorf(readerPos, "invalid character value"), data.EmptyIntSet
+			return nil, parsley.NewInvalidValueError(pos, readerPos, "character", string(res), err), data.EmptyIntSet
 		}
 
 		return ast.NewTerminalNode("CHAR", value, pos, readerPos), nil, data.EmptyIntSet
@@ -12,6 +12,9 @@ Sniperkit-Bot
 package terminal_test
 
 import (
+	"errors"
+	"strconv"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -85,17 +88,32 @@ var _ = Describe("Char", func() {
 	)
 
 	DescribeTable("should error",
-		func(input string, startPos int, errPos parsley.Pos) {
+		func(input string, startPos int, errPos parsley.Pos, errMsg string) {
 			f := text.NewFile("textfile", []byte(input))
 			r := text.NewReader(f)
 			res, err, curtailingParsers := p.Parse(nil, data.EmptyIntMap, r, f.Pos(startPos))
 			Expect(curtailingParsers).To(Equal(data.EmptyIntSet))
-			Expect(err).To(HaveOccurred())
+			Expect(err).To(MatchError(errMsg))
+			Expect(err.Pos()).To(Equal(errPos))
 			Expect(res).To(BeNil())
 		},
-		Entry("only start quote", `'`, 0, parsley.Pos(1)),
-		Entry("empty quotes", `''`, 0, parsley.Pos(2)),
-		Entry("no end quote", `'a`, 0, parsley.Pos(3)),
-		Entry("multiple characters", `'aa'`, 0, parsley.Pos(3)),
+		Entry("only start quote", `'`, 0, parsley.Pos(2), "unexpected end of input, was expecting one character"),
+		Entry("empty quotes", `''`, 0, parsley.Pos(2), `was expecting one character, found "'"`),
+		Entry("no end quote", `'a`, 0, parsley.Pos(3), `unexpected end of input, was expecting "'"`),
+		Entry("multiple characters", `'aa'`, 0, parsley.Pos(3), `was expecting "'", found "a"`),
 	)
+
+	It("should return an invalid value error for an invalid character", func() {
+		f := text.NewFile("textfile", []byte(`'\U00110000'`))
+		r := text.NewReader(f)
+		_, err, _ := p.Parse(nil, data.EmptyIntMap, r, f.Pos(0))
+		Expect(err).To(MatchError("invalid character value encountered"))
+		Expect(err.Pos()).To(Equal(parsley.Pos(1)))
+
+		var invalidErr *parsley.InvalidValueError
+		Expect(errors.As(err, &invalidErr)).To(BeTrue())
+		Expect(invalidErr.Type).To(Equal("character"))
+		Expect(invalidErr.Value).To(Equal(`\U00110000`))
+		Expect(errors.Is(err, strconv.ErrSyntax)).To(BeTrue())
+	})
 })
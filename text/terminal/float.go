@@ -21,14 +21,16 @@ import (
 	"github.com/sniperkit/snk.fork.parsley/text"
 )
 
+var floatRegexp = text.MustCompileRegexp("[-+]?[0-9]*\\.[0-9]+(?:[eE][-+]?[0-9]+)?")
+
 // Float matches a float literal
 func Float() *parser.NamedFunc {
 	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
 		tr := r.(*text.Reader)
-		if readerPos, result := tr.ReadRegexp(pos, "[-+]?[0-9]*\\.[0-9]+(?:[eE][-+]?[0-9]+)?"); result != nil {
+		if readerPos, result := tr.ReadCompiledRegexp(pos, floatRegexp); result != nil {
 			val, err := strconv.ParseFloat(string(result), 64)
 			if err != nil {
-				return nil, parsley.NewErrorf(pos, "invalid float value encountered"), data.EmptyIntSet
+				return nil, parsley.NewInvalidValueError(pos, readerPos, "float", string(result), err), data.EmptyIntSet
 			}
 			return ast.NewTerminalNode("FLOAT", val, pos, readerPos), nil, data.EmptyIntSet
 		}
@@ -12,6 +12,9 @@ Sniperkit-Bot
 package terminal_test
 
 import (
+	"errors"
+	"strconv"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -97,6 +100,8 @@ var _ = Describe("Float", func() {
 			Expect(err).To(MatchError("invalid float value encountered"))
 			Expect(err.Pos()).To(Equal(parsley.Pos(1)))
 			Expect(res).To(BeNil())
+			Expect(err).To(BeAssignableToTypeOf(&parsley.InvalidValueError{}))
+			Expect(errors.Is(err, strconv.ErrRange)).To(BeTrue())
 		})
 	})
 })
@@ -12,7 +12,6 @@ Sniperkit-Bot
 package terminal
 
 import (
-	"fmt"
 	"strconv"
 
 	"github.com/sniperkit/snk.fork.parsley/ast"
@@ -22,18 +21,19 @@ import (
 	"github.com/sniperkit/snk.fork.parsley/text"
 )
 
+var integerRegexp = text.MustCompileRegexp("[-+]?(?:[1-9][0-9]*|0[xX][0-9a-fA-F]+|0[0-7]*)")
+
 // Integer matches all integer numbers and zero with an optional -/+ sign
 func Integer() *parser.NamedFunc {
 	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
 		tr := r.(*text.Reader)
-		if readerPos, result := tr.ReadRegexp(pos, "[-+]?(?:[1-9][0-9]*|0[xX][0-9a-fA-F]+|0[0-7]*)"); result != nil {
+		if readerPos, result := tr.ReadCompiledRegexp(pos, integerRegexp); result != nil {
 			if _, isFloat := tr.ReadRune(readerPos, '.'); isFloat {
 				return nil, nil, data.EmptyIntSet
 			}
 			intValue, err := strconv.ParseInt(string(result), 0, 0)
 			if err != nil {
-				// This should never happen
-				panic(fmt.Sprintf("Could not convert %s to integer", string(result)))
+				return nil, parsley.NewInvalidValueError(pos, readerPos, "integer", string(result), err), data.EmptyIntSet
 			}
 			return ast.NewTerminalNode("INT", int(intValue), pos, readerPos), nil, data.EmptyIntSet
 		}
@@ -12,6 +12,9 @@ Sniperkit-Bot
 package terminal_test
 
 import (
+	"errors"
+	"strconv"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -85,4 +88,14 @@ var _ = Describe("Integer", func() {
 		Entry("float 0.1", "0.1", 0),
 		Entry("float 0.", "0.", 0),
 	)
+
+	It("should return an invalid value error if the value is out of range", func() {
+		f := text.NewFile("textfile", []byte("123456789012345678901234567890"))
+		r := text.NewReader(f)
+		res, err, _ := p.Parse(nil, data.EmptyIntMap, r, f.Pos(0))
+		Expect(res).To(BeNil())
+		Expect(err).To(MatchError("invalid integer value encountered"))
+		Expect(err.Pos()).To(Equal(parsley.Pos(1)))
+		Expect(errors.Is(err, strconv.ErrRange)).To(BeTrue())
+	})
 })
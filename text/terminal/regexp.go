@@ -25,21 +25,38 @@ import (
 // The name variable is used for error messages, so it should be descriptive and make sense in the sentence "was expecting %s".
 // The includeWhitespaces variable should be true if the reader is by default ignoring the whitespaces but you need to match those as well.
 // If you are using capturing groups you can select which group to use as a value with the groupIdex variable.
+// The regular expression is compiled when the parser is created and it panics if the expression is invalid, can match an
+// empty input or doesn't have the given capturing group. Use NewRegexp if you need the error instead.
 func Regexp(token string, name string, regexp string, groupIndex int) *parser.NamedFunc {
+	p, err := NewRegexp(token, name, regexp, groupIndex)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// NewRegexp creates the same parser as Regexp but returns with an error if the regular expression is invalid, can match
+// an empty input or doesn't have the given capturing group
+func NewRegexp(token string, name string, regexp string, groupIndex int) (*parser.NamedFunc, error) {
+	rc, err := text.CompileRegexp(regexp)
+	if err != nil {
+		return nil, err
+	}
+	if groupIndex > rc.NumSubexp() {
+		return nil, fmt.Errorf("capturing group %d is invalid for '%s'", groupIndex, regexp)
+	}
+
 	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
 		tr := r.(*text.Reader)
 		if groupIndex == 0 {
-			if readerPos, match := tr.ReadRegexp(pos, regexp); match != nil {
+			if readerPos, match := tr.ReadCompiledRegexp(pos, rc); match != nil {
 				return ast.NewTerminalNode(token, string(match), pos, readerPos), nil, data.EmptyIntSet
 			}
 		} else {
-			if readerPos, matches := tr.ReadRegexpSubmatch(pos, regexp); matches != nil {
-				if groupIndex >= len(matches) {
-					panic(fmt.Sprintf("Capturing group %d is invalid for %s", groupIndex, regexp))
-				}
+			if readerPos, matches := tr.ReadCompiledRegexpSubmatch(pos, rc); matches != nil {
 				return ast.NewTerminalNode(token, string(matches[groupIndex]), pos, readerPos), nil, data.EmptyIntSet
 			}
 		}
 		return nil, nil, data.EmptyIntSet
-	}).WithName(name)
+	}).WithName(name), nil
 }
@@ -34,17 +34,44 @@ var _ = Describe("Regexp", func() {
 
 	Context("when regexp matches an empty string", func() {
 		It("should panic", func() {
-			r := text.NewReader(text.NewFile("textfile", []byte("foo")))
-			p := terminal.Regexp("FOO", "foo", "f*", 0)
-			Expect(func() { p.Parse(nil, data.EmptyIntMap, r, 0) }).To(Panic())
+			Expect(func() { terminal.Regexp("FOO", "foo", "f*", 0) }).To(Panic())
+		})
+	})
+
+	Context("when regexp is invalid", func() {
+		It("should panic", func() {
+			Expect(func() { terminal.Regexp("FOO", "foo", "f(o+", 0) }).To(Panic())
 		})
 	})
 
 	Context("when capturing group is invalid", func() {
 		It("should panic", func() {
+			Expect(func() { terminal.Regexp("FOO", "foo", "f(o+)", 2) }).To(Panic())
+		})
+	})
+
+	Describe("NewRegexp", func() {
+		It("should return with the parser", func() {
+			p, err := terminal.NewRegexp("FOO", "foo", "fo+", 0)
+			Expect(err).ToNot(HaveOccurred())
 			r := text.NewReader(text.NewFile("textfile", []byte("foo")))
-			p := terminal.Regexp("FOO", "foo", "f(o+)", 2)
-			Expect(func() { p.Parse(nil, data.EmptyIntMap, r, 0) }).To(Panic())
+			res, _, _ := p.Parse(nil, data.EmptyIntMap, r, r.Pos(0))
+			Expect(res).To(Equal(ast.NewTerminalNode("FOO", "foo", r.Pos(0), r.Pos(3))))
+		})
+
+		It("should return an error if the regexp can match an empty input", func() {
+			_, err := terminal.NewRegexp("FOO", "foo", "f*", 0)
+			Expect(err).To(MatchError("'f*' is not allowed to match an empty input"))
+		})
+
+		It("should return an error if the capturing group is invalid", func() {
+			_, err := terminal.NewRegexp("FOO", "foo", "f(o+)", 2)
+			Expect(err).To(MatchError("capturing group 2 is invalid for 'f(o+)'"))
+		})
+
+		It("should return an error if the regexp is invalid", func() {
+			_, err := terminal.NewRegexp("FOO", "foo", "f(o+", 0)
+			Expect(err).To(HaveOccurred())
 		})
 	})
 
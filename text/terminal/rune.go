@@ -29,5 +29,5 @@ func Rune(ch rune) *parser.NamedFunc {
 			return ast.NewTerminalNode(string(ch), ch, pos, readerPos), nil, data.EmptyIntSet
 		}
 		return nil, nil, data.EmptyIntSet
-	}).WithName(fmt.Sprintf("%q", string(ch)))
+	}).WithName(fmt.Sprintf("%q", string(ch))).WithLiteral(string(ch), false)
 }
@@ -12,6 +12,7 @@ Sniperkit-Bot
 package terminal
 
 import (
+	"bytes"
 	"strconv"
 
 	"github.com/sniperkit/snk.fork.parsley/ast"
@@ -21,7 +22,10 @@ import (
 	"github.com/sniperkit/snk.fork.parsley/text"
 )
 
+var rawStringRegexp = text.MustCompileRegexp("[^`]+")
+
 // String matches a string literal enclosed in double quotes
+// If allowBackquote is true it also matches raw strings enclosed in backquotes, where the \r characters are discarded.
 func String(allowBackquote bool) *parser.NamedFunc {
 	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
 		tr := r.(*text.Reader)
@@ -46,14 +50,16 @@ func String(allowBackquote bool) *parser.NamedFunc {
 
 		var value []byte
 		if quote == '`' {
-			readerPos, value = tr.ReadRegexp(readerPos, "[^`]+")
+			readerPos, value = tr.ReadCompiledRegexp(readerPos, rawStringRegexp)
+			// carriage returns are discarded from raw strings, so the value doesn't depend on the line endings
+			value = bytes.Replace(value, []byte("\r"), nil, -1)
 		} else {
 			readerPos, value = tr.Readf(readerPos, unquoteString)
 		}
 
 		readerPos, found = tr.ReadRune(readerPos, quote)
 		if !found {
-			return nil, parsley.NewErrorf(readerPos, "was expecting '%s'", string(quote)), data.EmptyIntSet
+			return nil, parsley.DescribeError(r, parsley.NewExpectedError(readerPos, "'"+string(quote)+"'")), data.EmptyIntSet
 		}
 		return ast.NewTerminalNode("STRING", string(value), pos, readerPos), nil, data.EmptyIntSet
 	}).WithName("string value")
@@ -71,6 +71,7 @@ var _ = Describe("String", func() {
 			Entry("`"+`\x67`+"`", "`"+`\x67`+"`", 0, `\x67`, parsley.Pos(1), 6),
 			Entry("`"+`\uAB12`+"`", "`"+`\uAB12`+"`", 0, `\uAB12`, parsley.Pos(1), 8),
 			Entry("`"+`\U0001F355`+"`", "`"+`\U0001F355`+"`", 0, `\U0001F355`, parsley.Pos(1), 12),
+			Entry("`a\\r\\nb`", "`a\r\nb`", 0, "a\nb", parsley.Pos(1), 6), // Should discard the \r characters
 		)
 
 		DescribeTable("should not match",
@@ -94,7 +95,8 @@ var _ = Describe("String", func() {
 				r := text.NewReader(f)
 				res, err, curtailingParsers := p.Parse(nil, data.EmptyIntMap, r, f.Pos(0))
 				Expect(curtailingParsers).To(Equal(data.EmptyIntSet))
-				Expect(err).To(MatchError(fmt.Sprintf("was expecting '%s'", string(input[0]))))
+				Expect(err).To(MatchError(fmt.Sprintf("unexpected end of input, was expecting '%s'", string(input[0]))))
+				Expect(err).To(BeAssignableToTypeOf(&parsley.UnexpectedEOFError{}))
 				Expect(err.Pos()).To(Equal(parsley.Pos(5)))
 				Expect(res).To(BeNil())
 			},
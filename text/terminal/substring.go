@@ -33,5 +33,5 @@ func Substring(token string, str string, value interface{}) *parser.NamedFunc {
 			return ast.NewTerminalNode(token, value, pos, readerPos), nil, data.EmptyIntSet
 		}
 		return nil, nil, data.EmptyIntSet
-	}).WithName(fmt.Sprintf("%q", str))
+	}).WithName(fmt.Sprintf("%q", str)).WithLiteral(str, false)
 }
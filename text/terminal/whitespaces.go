@@ -19,7 +19,7 @@ import (
 	"github.com/sniperkit/snk.fork.parsley/text"
 )
 
-// Whitespaces matches one or more spaces or tabs. If newLine is true it also matches \n and \f characters.
+// Whitespaces matches one or more spaces or tabs. If newLine is true it also matches \n, \r and \f characters.
 func Whitespaces(wsMode text.WsMode) parsley.Parser {
 	if wsMode == text.WsNone {
 		return parser.Nil()
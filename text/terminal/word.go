@@ -33,5 +33,5 @@ func Word(word string, value interface{}) *parser.NamedFunc {
 			return ast.NewTerminalNode("WORD", value, pos, readerPos), nil, data.EmptyIntSet
 		}
 		return nil, nil, data.EmptyIntSet
-	}).WithName(fmt.Sprintf("%q", word))
+	}).WithName(fmt.Sprintf("%q", word)).WithLiteral(word, true)
 }
@@ -0,0 +1,94 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package token
+
+import (
+	"sort"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Reader defines a token stream reader
+// The reader positions are the start positions of the tokens, the end of input is at the given EOF position.
+type Reader struct {
+	tokens []Token
+	eof    parsley.Pos
+}
+
+// NewReader creates a new reader instance
+// The tokens should be ordered by their start position and every token should start at a different position.
+// The eof position is the end of the input (e.g. file.Pos(file.Len())), it should not be before the last token.
+func NewReader(tokens []Token, eof parsley.Pos) *Reader {
+	for i, t := range tokens {
+		if i > 0 && t.Span.Start <= tokens[i-1].Span.Start {
+			panic("the tokens should be ordered by their start position and should not start at the same position")
+		}
+		if t.Span.Start > eof {
+			panic("the tokens should not start after the end of input")
+		}
+	}
+	return &Reader{
+		tokens: tokens,
+		eof:    eof,
+	}
+}
+
+// index returns with the index of the next token at or after the given position
+func (r *Reader) index(pos parsley.Pos) int {
+	return sort.Search(len(r.tokens), func(i int) bool { return r.tokens[i].Span.Start >= pos })
+}
+
+// Peek returns with the next token at or after the given position
+func (r *Reader) Peek(pos parsley.Pos) (Token, bool) {
+	i := r.index(pos)
+	if i >= len(r.tokens) {
+		return Token{}, false
+	}
+	return r.tokens[i], true
+}
+
+// ReadToken returns with the next token and the position of the token after it
+func (r *Reader) ReadToken(pos parsley.Pos) (parsley.Pos, Token, bool) {
+	i := r.index(pos)
+	if i >= len(r.tokens) {
+		return pos, Token{}, false
+	}
+	return r.Pos(i + 1), r.tokens[i], true
+}
+
+// Advance returns with the position of the token after the next token
+func (r *Reader) Advance(pos parsley.Pos) parsley.Pos {
+	nextPos, _, _ := r.ReadToken(pos)
+	return nextPos
+}
+
+// Remaining returns with the remaining token count
+func (r *Reader) Remaining(pos parsley.Pos) int {
+	return len(r.tokens) - r.index(pos)
+}
+
+// IsEOF returns true if there are no more tokens
+func (r *Reader) IsEOF(pos parsley.Pos) bool {
+	return r.index(pos) >= len(r.tokens)
+}
+
+// Describe returns with the description of the next token or "end of input" if there are no more tokens
+func (r *Reader) Describe(pos parsley.Pos) string {
+	t, ok := r.Peek(pos)
+	if !ok {
+		return "end of input"
+	}
+	return t.String()
+}
+
+// Pos returns with the position of the token with the given index
+// If the index is after the last token then the EOF position is returned.
+func (r *Reader) Pos(cur int) parsley.Pos {
+	if cur >= len(r.tokens) {
+		return r.eof
+	}
+	return r.tokens[cur].Span.Start
+}
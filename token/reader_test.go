@@ -0,0 +1,125 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package token_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/token"
+)
+
+var _ = Describe("Reader", func() {
+
+	var (
+		r      *token.Reader
+		tokens []token.Token
+	)
+
+	BeforeEach(func() {
+		// a + 1
+		tokens = []token.Token{
+			token.NewToken("IDENT", "a", parsley.NewSpan(1, 2)),
+			token.NewToken("+", "+", parsley.NewSpan(3, 4)),
+			token.NewToken("NUMBER", "1", parsley.NewSpan(5, 6)),
+		}
+		r = token.NewReader(tokens, parsley.Pos(6))
+	})
+
+	It("should implement the parsley.Reader and parsley.Describer interfaces", func() {
+		var _ parsley.Reader = r
+		var _ parsley.Describer = r
+		var _ parsley.Advancer = r
+	})
+
+	It("should panic if the tokens are not ordered", func() {
+		Expect(func() { token.NewReader([]token.Token{tokens[1], tokens[0]}, parsley.Pos(6)) }).To(Panic())
+	})
+
+	It("should panic if a token starts after the end of input", func() {
+		Expect(func() { token.NewReader(tokens, parsley.Pos(4)) }).To(Panic())
+	})
+
+	Describe("Pos()", func() {
+		It("should return with the start position of the token", func() {
+			Expect(r.Pos(0)).To(Equal(parsley.Pos(1)))
+			Expect(r.Pos(2)).To(Equal(parsley.Pos(5)))
+		})
+
+		It("should return with the EOF position after the last token", func() {
+			Expect(r.Pos(3)).To(Equal(parsley.Pos(6)))
+		})
+	})
+
+	Describe("ReadToken()", func() {
+		It("should return with the next token and the position of the following token", func() {
+			pos, t, ok := r.ReadToken(parsley.Pos(3))
+			Expect(ok).To(BeTrue())
+			Expect(t).To(Equal(tokens[1]))
+			Expect(pos).To(Equal(parsley.Pos(5)))
+		})
+
+		It("should skip to the next token if the position is between tokens", func() {
+			_, t, ok := r.ReadToken(parsley.Pos(2))
+			Expect(ok).To(BeTrue())
+			Expect(t).To(Equal(tokens[1]))
+		})
+
+		It("should return with the EOF position after the last token", func() {
+			pos, _, ok := r.ReadToken(parsley.Pos(5))
+			Expect(ok).To(BeTrue())
+			Expect(pos).To(Equal(parsley.Pos(6)))
+		})
+
+		It("should return false at the end of input", func() {
+			pos, _, ok := r.ReadToken(parsley.Pos(6))
+			Expect(ok).To(BeFalse())
+			Expect(pos).To(Equal(parsley.Pos(6)))
+		})
+	})
+
+	Describe("Advance()", func() {
+		It("should return with the position of the next token", func() {
+			Expect(r.Advance(parsley.Pos(1))).To(Equal(parsley.Pos(3)))
+			Expect(r.Advance(parsley.Pos(2))).To(Equal(parsley.Pos(5)))
+			Expect(r.Advance(parsley.Pos(5))).To(Equal(parsley.Pos(6)))
+		})
+	})
+
+	Describe("Peek()", func() {
+		It("should return with the next token", func() {
+			t, ok := r.Peek(parsley.Pos(1))
+			Expect(ok).To(BeTrue())
+			Expect(t).To(Equal(tokens[0]))
+		})
+
+		It("should return false at the end of input", func() {
+			_, ok := r.Peek(parsley.Pos(6))
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Remaining() and IsEOF()", func() {
+		It("should return with the remaining token count", func() {
+			Expect(r.Remaining(parsley.Pos(1))).To(Equal(3))
+			Expect(r.Remaining(parsley.Pos(4))).To(Equal(1))
+			Expect(r.IsEOF(parsley.Pos(5))).To(BeFalse())
+			Expect(r.Remaining(parsley.Pos(6))).To(Equal(0))
+			Expect(r.IsEOF(parsley.Pos(6))).To(BeTrue())
+		})
+	})
+
+	Describe("Describe()", func() {
+		It("should describe the next token", func() {
+			Expect(r.Describe(parsley.Pos(1))).To(Equal(`IDENT "a"`))
+		})
+
+		It("should return end of input at the end", func() {
+			Expect(r.Describe(parsley.Pos(6))).To(Equal("end of input"))
+		})
+	})
+})
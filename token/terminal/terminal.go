@@ -0,0 +1,7 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Package terminal contains terminal parsers for token streams
+package terminal
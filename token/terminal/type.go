@@ -0,0 +1,47 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal
+
+import (
+	"strconv"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/token"
+)
+
+// Type matches the next token if it has the given type
+// The node's token is the token type and its value is the token's value or text.
+func Type(tokenType string) *parser.NamedFunc {
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		tr := r.(*token.Reader)
+		if readerPos, t, ok := tr.ReadToken(pos); ok && t.Type == tokenType {
+			return newNode(t, readerPos), nil, data.EmptyIntSet
+		}
+		return nil, nil, data.EmptyIntSet
+	}).WithName(tokenType)
+}
+
+// Value matches the next token if it has the given type and text (e.g. a keyword or an operator)
+func Value(tokenType string, text string) *parser.NamedFunc {
+	return parser.Func(func(h parsley.History, leftRecCtx data.IntMap, r parsley.Reader, pos parsley.Pos) (parsley.Node, parsley.Error, data.IntSet) {
+		tr := r.(*token.Reader)
+		if readerPos, t, ok := tr.ReadToken(pos); ok && t.Type == tokenType && t.Text == text {
+			return newNode(t, readerPos), nil, data.EmptyIntSet
+		}
+		return nil, nil, data.EmptyIntSet
+	}).WithName(strconv.Quote(text))
+}
+
+func newNode(t token.Token, readerPos parsley.Pos) *ast.TerminalNode {
+	var value interface{} = t.Text
+	if t.Value != nil {
+		value = t.Value
+	}
+	return ast.NewTerminalNode(t.Type, value, t.Span.Start, readerPos)
+}
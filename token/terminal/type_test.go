@@ -0,0 +1,149 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package terminal_test
+
+import (
+	"fmt"
+	"regexp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/ast"
+	"github.com/sniperkit/snk.fork.parsley/combinator"
+	"github.com/sniperkit/snk.fork.parsley/data"
+	"github.com/sniperkit/snk.fork.parsley/parser"
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/text"
+	"github.com/sniperkit/snk.fork.parsley/token"
+	"github.com/sniperkit/snk.fork.parsley/token/terminal"
+)
+
+var tokenRegexp = regexp.MustCompile(`[a-z]+|[0-9]+|[=;]`)
+
+// lex is a very simple lexer for the tests
+func lex(f *text.File, input string) []token.Token {
+	var tokens []token.Token
+	for _, loc := range tokenRegexp.FindAllStringIndex(input, -1) {
+		s := input[loc[0]:loc[1]]
+		tokenType := s
+		switch {
+		case s == "let":
+			tokenType = "KEYWORD"
+		case s[0] >= 'a' && s[0] <= 'z':
+			tokenType = "IDENT"
+		case s[0] >= '0' && s[0] <= '9':
+			tokenType = "NUMBER"
+		}
+		tokens = append(tokens, token.NewToken(tokenType, s, parsley.NewSpan(f.Pos(loc[0]), f.Pos(loc[1]))))
+	}
+	return tokens
+}
+
+// Let's parse a simple assignment from pre-lexed tokens
+func ExampleType() {
+	input := "let x = 42;"
+	f := text.NewFile("example.file", []byte(input))
+	r := token.NewReader(lex(f, input), f.Pos(f.Len()))
+
+	p := combinator.Seq("LET", "let statement",
+		terminal.Value("KEYWORD", "let"),
+		terminal.Type("IDENT"),
+		terminal.Value("=", "="),
+		terminal.Type("NUMBER"),
+		terminal.Value(";", ";"),
+	)
+	node, _ := parsley.Parse(parser.NewHistory(), r, combinator.Sentence(p))
+	fmt.Println(node)
+	// Output: S{[LET{[KEYWORD{let, 1..5} IDENT{x, 5..7} ={=, 7..9} NUMBER{42, 9..11} ;{;, 11..12}], 1..12} EOF{<nil>, 12..12}], 1..12}
+}
+
+var _ = Describe("Token terminals", func() {
+
+	var (
+		f     *text.File
+		r     *token.Reader
+		input string
+	)
+
+	BeforeEach(func() {
+		input = "let x = 42"
+	})
+
+	JustBeforeEach(func() {
+		f = text.NewFile("testfile", []byte(input))
+		r = token.NewReader(lex(f, input), f.Pos(f.Len()))
+	})
+
+	Describe("Type()", func() {
+		It("should have the type as name", func() {
+			Expect(terminal.Type("IDENT").Name()).To(Equal("IDENT"))
+		})
+
+		It("should match a token with the given type", func() {
+			res, err, cp := terminal.Type("IDENT").Parse(nil, data.EmptyIntMap, r, f.Pos(4))
+			Expect(cp).To(Equal(data.EmptyIntSet))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(ast.NewTerminalNode("IDENT", "x", f.Pos(4), f.Pos(6))))
+		})
+
+		It("should not match a token with a different type", func() {
+			res, err, _ := terminal.Type("NUMBER").Parse(nil, data.EmptyIntMap, r, f.Pos(4))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should not match at the end of input", func() {
+			res, err, _ := terminal.Type("NUMBER").Parse(nil, data.EmptyIntMap, r, f.Pos(10))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should use the token value if set", func() {
+			tokens := []token.Token{{Type: "NUMBER", Text: "42", Value: 42, Span: parsley.NewSpan(1, 3)}}
+			res, _, _ := terminal.Type("NUMBER").Parse(nil, data.EmptyIntMap, token.NewReader(tokens, 3), 1)
+			Expect(res.Value(nil)).To(Equal(42))
+		})
+	})
+
+	Describe("Value()", func() {
+		It("should have the quoted text as name", func() {
+			Expect(terminal.Value("KEYWORD", "let").Name()).To(Equal(`"let"`))
+		})
+
+		It("should match a token with the given type and text", func() {
+			res, err, _ := terminal.Value("KEYWORD", "let").Parse(nil, data.EmptyIntMap, r, f.Pos(0))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(ast.NewTerminalNode("KEYWORD", "let", f.Pos(0), f.Pos(4))))
+		})
+
+		It("should not match a token with a different text", func() {
+			res, err, _ := terminal.Value("IDENT", "y").Parse(nil, data.EmptyIntMap, r, f.Pos(4))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+	})
+
+	Context("when parsing with a file set", func() {
+		BeforeEach(func() {
+			input = "let x = \n  y"
+		})
+
+		It("should return with errors mapping back to the original file", func() {
+			p := combinator.Seq("LET", "let statement",
+				terminal.Value("KEYWORD", "let"),
+				terminal.Type("IDENT"),
+				terminal.Value("=", "="),
+				terminal.Type("NUMBER"),
+			)
+			_, err := parsley.Parse(parser.NewHistory(), r, combinator.Sentence(p))
+			Expect(err).To(MatchError(`failed to parse the input: was expecting NUMBER, found IDENT "y"`))
+
+			fs := parsley.NewFileSet(f)
+			Expect(fs.Position(err.Pos()).String()).To(Equal("testfile:2:3"))
+		})
+	})
+})
@@ -0,0 +1,44 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+// Package token defines a reader over a pre-lexed token stream, so the lexing can run once before the parsing.
+// The reader positions are the positions of the tokens in the original input, so all the node and error positions
+// can be resolved through the parsley.FileSet.
+package token
+
+import (
+	"strconv"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+)
+
+// Token is a lexical token
+type Token struct {
+	// Type is the token type (e.g. IDENT, NUMBER or "+")
+	Type string
+	// Text is the matched input
+	Text string
+	// Value is the optional converted value of the token, the text is used as value if nil
+	Value interface{}
+	// Span is the range of the token in the original input
+	Span parsley.Span
+}
+
+// NewToken creates a new token
+func NewToken(tokenType string, text string, span parsley.Span) Token {
+	return Token{
+		Type: tokenType,
+		Text: text,
+		Span: span,
+	}
+}
+
+// String returns with the token type and the quoted text
+func (t Token) String() string {
+	if t.Text == t.Type {
+		return strconv.Quote(t.Text)
+	}
+	return t.Type + " " + strconv.Quote(t.Text)
+}
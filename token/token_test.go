@@ -0,0 +1,26 @@
+/*
+Sniperkit-Bot
+- Status: analyzed
+*/
+
+package token_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/sniperkit/snk.fork.parsley/parsley"
+	"github.com/sniperkit/snk.fork.parsley/token"
+)
+
+var _ = Describe("Token", func() {
+	It("should contain the type and the text in the string representation", func() {
+		t := token.NewToken("IDENT", "foo", parsley.NewSpan(1, 4))
+		Expect(t.String()).To(Equal(`IDENT "foo"`))
+	})
+
+	It("should only contain the text if it's the same as the type", func() {
+		t := token.NewToken("+", "+", parsley.NewSpan(1, 2))
+		Expect(t.String()).To(Equal(`"+"`))
+	})
+})